@@ -15,30 +15,265 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/v1/alerts/{latitude}/{longitude}": {
+            "get": {
+                "description": "Get the NWS watches, warnings and advisories in effect at the coordinates",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "summary": "Returns the active weather alerts by latitude and longitude coordinates",
+                "operationId": "get-alerts-by-coordinates",
+                "parameters": [
+                    {
+                        "maximum": 90,
+                        "minimum": -90,
+                        "type": "number",
+                        "format": "float",
+                        "description": "The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals",
+                        "name": "latitude",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 180,
+                        "minimum": -180,
+                        "type": "number",
+                        "format": "float",
+                        "description": "The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals",
+                        "name": "longitude",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "Extreme",
+                                "Severe",
+                                "Moderate",
+                                "Minor",
+                                "Unknown"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Only return alerts with one of these severities",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Only return these events (e.g. Tornado Warning)",
+                        "name": "event",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Alerts"
+                        },
+                        "headers": {
+                            "Age": {
+                                "type": "integer",
+                                "description": "Seconds since stale alerts were fetched from NWS"
+                            },
+                            "Warning": {
+                                "type": "string",
+                                "description": "Warning code 110 (Response is Stale) when stale alerts are served"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The coordinates or query parameters are invalid",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "NWS returned an error or an unreadable response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "NWS is down or throttling requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "NWS did not answer in time",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/conditions/{latitude}/{longitude}": {
+            "get": {
+                "description": "Get the latest observation from the nearest NWS station with a recent one",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "summary": "Returns the current observed conditions by latitude and longitude coordinates",
+                "operationId": "get-conditions-by-coordinates",
+                "parameters": [
+                    {
+                        "maximum": 90,
+                        "minimum": -90,
+                        "type": "number",
+                        "format": "float",
+                        "description": "The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals",
+                        "name": "latitude",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 180,
+                        "minimum": -180,
+                        "type": "number",
+                        "format": "float",
+                        "description": "The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals",
+                        "name": "longitude",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "us",
+                            "si",
+                            "metric"
+                        ],
+                        "type": "string",
+                        "description": "Unit system of the response; the server's default, us unless configured otherwise, when not given",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Conditions"
+                        },
+                        "headers": {
+                            "Age": {
+                                "type": "integer",
+                                "description": "Seconds since a stale observation was fetched from NWS"
+                            },
+                            "Warning": {
+                                "type": "string",
+                                "description": "Warning code 110 (Response is Stale) when a stale observation is served"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The coordinates or query parameters are invalid",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "NWS has no data for the location",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "NWS returned an error or an unreadable response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "NWS is down, throttling requests or no nearby station has a recent observation",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "NWS did not answer in time",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/forecasts/{latitude}/{longitude}": {
             "get": {
                 "description": "Get Forecast By Coordinates",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "summary": "Returns the forecasted weather by latitude and longitude coordinates",
                 "operationId": "get-forecast-by-coordinates",
                 "parameters": [
                     {
+                        "maximum": 90,
+                        "minimum": -90,
                         "type": "number",
                         "format": "float",
-                        "description": "The latitude of the desired location  (e.g. 39.7456)",
+                        "description": "The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals",
                         "name": "latitude",
                         "in": "path",
                         "required": true
                     },
                     {
+                        "maximum": 180,
+                        "minimum": -180,
                         "type": "number",
-                        "format": "flaot",
-                        "description": "The longitude of the desired location  (e.g. -97.0892)",
+                        "format": "float",
+                        "description": "The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals",
                         "name": "longitude",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "alerts"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Extra data to include in the forecast",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "us",
+                            "si",
+                            "metric"
+                        ],
+                        "type": "string",
+                        "description": "Unit system of the response; the server's default, us unless configured otherwise, when not given",
+                        "name": "units",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -46,12 +281,361 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.Forecast"
+                        },
+                        "headers": {
+                            "Age": {
+                                "type": "integer",
+                                "description": "Seconds since a stale forecast was fetched from NWS"
+                            },
+                            "Warning": {
+                                "type": "string",
+                                "description": "Warning code 110 (Response is Stale) when a stale forecast is served"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The coordinates or query parameters are invalid",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "NWS has no forecast for the location",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "NWS returned an error or an unreadable response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "NWS is down, throttling requests or has no forecast data right now",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "NWS did not answer in time",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/forecasts/{latitude}/{longitude}/daily": {
+            "get": {
+                "description": "Get one summary per local calendar day with the high, low, dominant condition, highest probability of precipitation and the day and night forecasts",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "summary": "Returns the forecast per day by latitude and longitude coordinates",
+                "operationId": "get-daily-forecast-by-coordinates",
+                "parameters": [
+                    {
+                        "maximum": 90,
+                        "minimum": -90,
+                        "type": "number",
+                        "format": "float",
+                        "description": "The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals",
+                        "name": "latitude",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 180,
+                        "minimum": -180,
+                        "type": "number",
+                        "format": "float",
+                        "description": "The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals",
+                        "name": "longitude",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "us",
+                            "si",
+                            "metric"
+                        ],
+                        "type": "string",
+                        "description": "Unit system of the response; the server's default, us unless configured otherwise, when not given",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DailyForecast"
+                        },
+                        "headers": {
+                            "Age": {
+                                "type": "integer",
+                                "description": "Seconds since a stale forecast was fetched from NWS"
+                            },
+                            "Warning": {
+                                "type": "string",
+                                "description": "Warning code 110 (Response is Stale) when a stale forecast is served"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The coordinates or query parameters are invalid",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "NWS has no forecast for the location",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "NWS returned an error or an unreadable response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "NWS is down, throttling requests or has no forecast data right now",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "NWS did not answer in time",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/forecasts/{latitude}/{longitude}/hourly": {
+            "get": {
+                "description": "Get the hour by hour forecast, starting with the current hour, by coordinates",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "summary": "Returns the hourly forecast by latitude and longitude coordinates",
+                "operationId": "get-hourly-forecast-by-coordinates",
+                "parameters": [
+                    {
+                        "maximum": 90,
+                        "minimum": -90,
+                        "type": "number",
+                        "format": "float",
+                        "description": "The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals",
+                        "name": "latitude",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 180,
+                        "minimum": -180,
+                        "type": "number",
+                        "format": "float",
+                        "description": "The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals",
+                        "name": "longitude",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 156,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "How many hours to return; all that NWS forecasts by default",
+                        "name": "hours",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "RFC 3339 time of the first hour to return (e.g. 2024-03-01T15:00:00-06:00); now by default",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "us",
+                            "si",
+                            "metric"
+                        ],
+                        "type": "string",
+                        "description": "Unit system of the response; the server's default, us unless configured otherwise, when not given",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HourlyForecast"
+                        },
+                        "headers": {
+                            "Age": {
+                                "type": "integer",
+                                "description": "Seconds since a stale forecast was fetched from NWS"
+                            },
+                            "Warning": {
+                                "type": "string",
+                                "description": "Warning code 110 (Response is Stale) when a stale forecast is served"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The coordinates or query parameters are invalid",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "NWS has no hourly forecast for the location",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "NWS returned an error or an unreadable response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "NWS is down, throttling requests or has no forecast data right now",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "NWS did not answer in time",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/forecasts/{latitude}/{longitude}/periods": {
+            "get": {
+                "description": "Get the 7 day outlook, usually 14 day and night periods, by coordinates",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "summary": "Returns every period of the multi-day forecast by latitude and longitude coordinates",
+                "operationId": "get-forecast-periods-by-coordinates",
+                "parameters": [
+                    {
+                        "maximum": 90,
+                        "minimum": -90,
+                        "type": "number",
+                        "format": "float",
+                        "description": "The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals",
+                        "name": "latitude",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 180,
+                        "minimum": -180,
+                        "type": "number",
+                        "format": "float",
+                        "description": "The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals",
+                        "name": "longitude",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "us",
+                            "si",
+                            "metric"
+                        ],
+                        "type": "string",
+                        "description": "Unit system of the response; the server's default, us unless configured otherwise, when not given",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ForecastPeriods"
+                        },
+                        "headers": {
+                            "Age": {
+                                "type": "integer",
+                                "description": "Seconds since a stale forecast was fetched from NWS"
+                            },
+                            "Warning": {
+                                "type": "string",
+                                "description": "Warning code 110 (Response is Stale) when a stale forecast is served"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The coordinates or query parameters are invalid",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "NWS has no forecast for the location",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.APIError"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "NWS returned an error or an unreadable response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "NWS is down, throttling requests or has no forecast data right now",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "NWS did not answer in time",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
@@ -59,11 +643,94 @@ const docTemplate = `{
         }
     },
     "definitions": {
-        "models.APIError": {
+        "models.Alert": {
             "type": "object",
             "properties": {
-                "error": {
+                "affected_zones": {
+                    "description": "AffectedZones are the NWS zone URLs the alert applies to",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "area_description": {
+                    "description": "AreaDescription names the affected area, e.g. \"Cook, IL\"",
+                    "type": "string"
+                },
+                "certainty": {
+                    "type": "string",
+                    "example": "Observed"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string",
+                    "example": "Tornado Warning"
+                },
+                "expires": {
+                    "type": "string"
+                },
+                "headline": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "instruction": {
                     "type": "string"
+                },
+                "severity": {
+                    "type": "string",
+                    "enum": [
+                        "Extreme",
+                        "Severe",
+                        "Moderate",
+                        "Minor",
+                        "Unknown"
+                    ],
+                    "example": "Extreme"
+                },
+                "urgency": {
+                    "type": "string",
+                    "example": "Immediate"
+                }
+            }
+        },
+        "models.AlertSummary": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "type": "string",
+                    "example": "Tornado Warning"
+                },
+                "expires": {
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "string",
+                    "example": "Extreme"
+                }
+            }
+        },
+        "models.Alerts": {
+            "type": "object",
+            "properties": {
+                "age_seconds": {
+                    "type": "integer"
+                },
+                "alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Alert"
+                    }
+                },
+                "stale": {
+                    "description": "Stale is set when the response was served from the cache after it\nexpired, either while refreshing it or because NWS was unavailable.",
+                    "type": "boolean"
                 }
             }
         },
@@ -82,19 +749,454 @@ const docTemplate = `{
                 "Unknown"
             ]
         },
+        "models.Conditions": {
+            "type": "object",
+            "properties": {
+                "age_seconds": {
+                    "type": "integer"
+                },
+                "barometric_pressure": {
+                    "$ref": "#/definitions/models.Measurement"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Mostly Cloudy"
+                },
+                "dewpoint": {
+                    "$ref": "#/definitions/models.Measurement"
+                },
+                "distance_km": {
+                    "description": "DistanceKm is how far the station is from the requested location",
+                    "type": "number",
+                    "example": 12.3
+                },
+                "observed_at": {
+                    "type": "string"
+                },
+                "relative_humidity": {
+                    "$ref": "#/definitions/models.Measurement"
+                },
+                "stale": {
+                    "description": "Stale is set when the response was served from the cache after it\nexpired, either while refreshing it or because NWS was unavailable.",
+                    "type": "boolean"
+                },
+                "station_id": {
+                    "type": "string",
+                    "example": "KTOP"
+                },
+                "station_name": {
+                    "type": "string",
+                    "example": "Topeka, Billard Municipal Airport"
+                },
+                "temperature": {
+                    "$ref": "#/definitions/models.Measurement"
+                },
+                "units": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.UnitSystem"
+                        }
+                    ],
+                    "example": "si"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/models.Measurement"
+                },
+                "wind_direction": {
+                    "$ref": "#/definitions/models.Measurement"
+                },
+                "wind_gust": {
+                    "$ref": "#/definitions/models.Measurement"
+                },
+                "wind_speed": {
+                    "$ref": "#/definitions/models.Measurement"
+                }
+            }
+        },
+        "models.DailyForecast": {
+            "type": "object",
+            "properties": {
+                "age_seconds": {
+                    "type": "integer"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DayForecast"
+                    }
+                },
+                "stale": {
+                    "description": "Stale is set when the response was served from the cache after it\nexpired, either while refreshing it or because NWS was unavailable.",
+                    "type": "boolean"
+                },
+                "units": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.UnitSystem"
+                        }
+                    ],
+                    "example": "us"
+                }
+            }
+        },
+        "models.DayForecast": {
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "description": "Condition is the short forecast that covers most of the day",
+                    "type": "string",
+                    "example": "Partly Sunny"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-03-01"
+                },
+                "day": {
+                    "$ref": "#/definitions/models.DaySummary"
+                },
+                "high": {
+                    "description": "High is the highest temperature of the day, in temperature_unit",
+                    "type": "integer",
+                    "example": 72
+                },
+                "low": {
+                    "description": "Low is the lowest temperature of the day, in temperature_unit",
+                    "type": "integer",
+                    "example": 48
+                },
+                "night": {
+                    "$ref": "#/definitions/models.DaySummary"
+                },
+                "probability_of_precipitation": {
+                    "description": "ProbabilityOfPrecipitation is the highest of the day, as a percentage",
+                    "type": "integer",
+                    "example": 40
+                },
+                "temperature_unit": {
+                    "type": "string",
+                    "example": "F"
+                }
+            }
+        },
+        "models.DaySummary": {
+            "type": "object",
+            "properties": {
+                "detailed_forecast": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Saturday"
+                },
+                "short_forecast": {
+                    "type": "string",
+                    "example": "Partly Sunny"
+                },
+                "temperature": {
+                    "description": "Temperature is in the temperature_unit of the day",
+                    "type": "integer",
+                    "example": 72
+                }
+            }
+        },
         "models.Forecast": {
             "type": "object",
             "properties": {
+                "age_seconds": {
+                    "type": "integer"
+                },
+                "alerts": {
+                    "description": "Alerts in effect, only included when asked for. AlertsError says\nwhy they are missing when they could not be fetched, and AlertsStale\nthat they were served from the cache after they expired, so newer\nalerts may be missing.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AlertSummary"
+                    }
+                },
+                "alerts_age_seconds": {
+                    "type": "integer"
+                },
+                "alerts_error": {
+                    "type": "string"
+                },
+                "alerts_stale": {
+                    "type": "boolean"
+                },
                 "forecast_daily": {
                     "type": "string"
                 },
+                "period": {
+                    "description": "Period is the NWS forecast period the forecast was taken from",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Period"
+                        }
+                    ]
+                },
+                "stale": {
+                    "description": "Stale is set when the response was served from the cache after it\nexpired, either while refreshing it or because NWS was unavailable.",
+                    "type": "boolean"
+                },
                 "temperature": {
                     "type": "integer"
                 },
                 "temperature_characterization": {
                     "$ref": "#/definitions/models.Characterization"
+                },
+                "temperature_unit": {
+                    "type": "string",
+                    "example": "F"
+                },
+                "units": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.UnitSystem"
+                        }
+                    ],
+                    "example": "us"
                 }
             }
+        },
+        "models.ForecastPeriods": {
+            "type": "object",
+            "properties": {
+                "age_seconds": {
+                    "type": "integer"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PeriodForecast"
+                    }
+                },
+                "stale": {
+                    "description": "Stale is set when the response was served from the cache after it\nexpired, either while refreshing it or because NWS was unavailable.",
+                    "type": "boolean"
+                },
+                "units": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.UnitSystem"
+                        }
+                    ],
+                    "example": "us"
+                }
+            }
+        },
+        "models.HourForecast": {
+            "type": "object",
+            "properties": {
+                "dewpoint": {
+                    "description": "Dewpoint is in temperature_unit, null when NWS gives none",
+                    "type": "integer",
+                    "example": 55
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "is_daytime": {
+                    "type": "boolean"
+                },
+                "probability_of_precipitation": {
+                    "description": "ProbabilityOfPrecipitation is a percentage, null when NWS gives none",
+                    "type": "integer",
+                    "example": 20
+                },
+                "relative_humidity": {
+                    "description": "RelativeHumidity is a percentage, null when NWS gives none",
+                    "type": "integer",
+                    "example": 60
+                },
+                "short_forecast": {
+                    "type": "string",
+                    "example": "Partly Sunny"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "temperature": {
+                    "type": "integer",
+                    "example": 72
+                },
+                "temperature_characterization": {
+                    "$ref": "#/definitions/models.Characterization"
+                },
+                "temperature_unit": {
+                    "type": "string",
+                    "example": "F"
+                },
+                "wind_direction": {
+                    "type": "string",
+                    "example": "SW"
+                },
+                "wind_speed": {
+                    "type": "string",
+                    "example": "10 mph"
+                }
+            }
+        },
+        "models.HourlyForecast": {
+            "type": "object",
+            "properties": {
+                "age_seconds": {
+                    "type": "integer"
+                },
+                "hours": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HourForecast"
+                    }
+                },
+                "stale": {
+                    "description": "Stale is set when the response was served from the cache after it\nexpired, either while refreshing it or because NWS was unavailable.",
+                    "type": "boolean"
+                },
+                "units": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.UnitSystem"
+                        }
+                    ],
+                    "example": "us"
+                }
+            }
+        },
+        "models.Measurement": {
+            "type": "object",
+            "properties": {
+                "unit": {
+                    "type": "string",
+                    "example": "degC"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 21.7
+                }
+            }
+        },
+        "models.Period": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "is_daytime": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Today"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PeriodForecast": {
+            "type": "object",
+            "properties": {
+                "detailed_forecast": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "is_daytime": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Today"
+                },
+                "number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "probability_of_precipitation": {
+                    "description": "ProbabilityOfPrecipitation is a percentage, null when NWS gives none",
+                    "type": "integer",
+                    "example": 20
+                },
+                "short_forecast": {
+                    "type": "string",
+                    "example": "Partly Sunny"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "temperature": {
+                    "type": "integer",
+                    "example": 72
+                },
+                "temperature_characterization": {
+                    "$ref": "#/definitions/models.Characterization"
+                },
+                "temperature_trend": {
+                    "type": "string",
+                    "example": "falling"
+                },
+                "temperature_unit": {
+                    "type": "string",
+                    "example": "F"
+                },
+                "wind_direction": {
+                    "type": "string",
+                    "example": "SW"
+                },
+                "wind_speed": {
+                    "type": "string",
+                    "example": "5 to 10 mph"
+                }
+            }
+        },
+        "models.Problem": {
+            "type": "object",
+            "properties": {
+                "correlation_id": {
+                    "description": "CorrelationID identifies the failed request in NWS's logs",
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "Unexpected Problem"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/v1/forecasts/39.7456/-97.0892"
+                },
+                "request_id": {
+                    "description": "RequestID identifies the request in our logs",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 502
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Bad Gateway"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "models.UnitSystem": {
+            "type": "string",
+            "enum": [
+                "us",
+                "si"
+            ],
+            "x-enum-varnames": [
+                "US",
+                "SI"
+            ]
         }
     }
 }`
@@ -1,8 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	// Forecast periods are chosen in the location's time zone, which must
+	// not depend on the host having zoneinfo installed
+	_ "time/tzdata"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -18,28 +30,520 @@ import (
 //	@Summary		Returns the forecasted weather by latitude and longitude coordinates
 //	@Description	Get Forecast By Coordinates
 //	@ID				get-forecast-by-coordinates
-//	@Produce		json
-//	@Param			latitude	 path	    number true	"The latitude of the desired location  (e.g. 39.7456)" Format(float)
-//	@Param			longitude	 path	    number true	"The longitude of the desired location  (e.g. -97.0892)" Format(flaot)
+//	@Produce		json,application/problem+json
+//	@Param			latitude	 path	    number true	"The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals" Format(float) minimum(-90) maximum(90)
+//	@Param			longitude	 path	    number true	"The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals" Format(float) minimum(-180) maximum(180)
+//	@Param			include		 query	    []string false	"Extra data to include in the forecast" collectionFormat(csv) Enums(alerts)
+//	@Param			units		 query	    string false	"Unit system of the response; the server's default, us unless configured otherwise, when not given" Enums(us, si, metric)
 //	@Success		200		{object}	models.Forecast
-//	@Failure	    500		{object}	models.APIError
+//	@Header			200		{integer}	Age		"Seconds since a stale forecast was fetched from NWS"
+//	@Header			200		{string}	Warning	"Warning code 110 (Response is Stale) when a stale forecast is served"
+//	@Failure	    400		{object}	models.Problem	"The coordinates or query parameters are invalid"
+//	@Failure	    404		{object}	models.Problem	"NWS has no forecast for the location"
+//	@Failure	    500		{object}	models.Problem
+//	@Failure	    502		{object}	models.Problem	"NWS returned an error or an unreadable response"
+//	@Failure	    503		{object}	models.Problem	"NWS is down, throttling requests or has no forecast data right now"
+//	@Failure	    504		{object}	models.Problem	"NWS did not answer in time"
 //	@Router			/v1/forecasts/{latitude}/{longitude} [get]
-func GetForecast(w http.ResponseWriter, r *http.Request) {
-	w.Header().Add("Content-Type", "application/json; charset=utf-8")
+func GetForecast(client services.WeatherClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		coordinate, err := coordinateParams(r)
 
-	latitude := chi.URLParam(r, "latitude")
-	longitude := chi.URLParam(r, "longitude")
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
 
-	client := services.NewClient()
-	forecast, err := client.GetForecast(latitude, longitude)
+		units, err := unitsParam(r)
 
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		utils.JSONResponse(w, models.APIError{Message: err.Error()})
-		return
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		includeAlerts, err := includeParam(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		// Alerts are fetched alongside the forecast, and failing to get them
+		// does not fail the forecast
+		var alerts *models.Alerts
+		var alertsErr error
+		var wg sync.WaitGroup
+
+		if includeAlerts {
+			wg.Go(func() {
+				alerts, alertsErr = client.GetAlerts(r.Context(), coordinate, models.AlertQuery{})
+			})
+		}
+
+		forecast, err := client.GetForecast(r.Context(), coordinate, units)
+		wg.Wait()
+
+		if err != nil {
+			utils.ProblemResponse(w, clientProblem(r, err))
+			return
+		}
+
+		if includeAlerts {
+			if alertsErr != nil {
+				log.Printf("forecast for %s without alerts: %v", coordinate, alertsErr)
+				forecast.AlertsError = alertsErr.Error()
+			} else {
+				forecast.Alerts = alerts.Summaries()
+				forecast.AlertsStale = alerts.Stale
+				forecast.AlertsAgeSeconds = alerts.AgeSeconds
+			}
+		}
+
+		w.Header().Add("Content-Type", "application/json; charset=utf-8")
+		staleHeaders(w, forecast.Stale, forecast.AgeSeconds)
+		utils.JSONResponse(w, forecast)
+	}
+}
+
+// includeParam parses the include query parameter, which can only ask for
+// alerts for now.
+func includeParam(r *http.Request) (alerts bool, err error) {
+	for _, value := range listParam(r, "include") {
+		if value != "alerts" {
+			return false, fmt.Errorf("invalid include %q: must be alerts", value)
+		}
+
+		alerts = true
+	}
+
+	return alerts, nil
+}
+
+// GetForecastPeriods
+//
+//	@Summary		Returns every period of the multi-day forecast by latitude and longitude coordinates
+//	@Description	Get the 7 day outlook, usually 14 day and night periods, by coordinates
+//	@ID				get-forecast-periods-by-coordinates
+//	@Produce		json,application/problem+json
+//	@Param			latitude	 path	    number true	"The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals" Format(float) minimum(-90) maximum(90)
+//	@Param			longitude	 path	    number true	"The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals" Format(float) minimum(-180) maximum(180)
+//	@Param			units		 query	    string false	"Unit system of the response; the server's default, us unless configured otherwise, when not given" Enums(us, si, metric)
+//	@Success		200		{object}	models.ForecastPeriods
+//	@Header			200		{integer}	Age		"Seconds since a stale forecast was fetched from NWS"
+//	@Header			200		{string}	Warning	"Warning code 110 (Response is Stale) when a stale forecast is served"
+//	@Failure	    400		{object}	models.Problem	"The coordinates or query parameters are invalid"
+//	@Failure	    404		{object}	models.Problem	"NWS has no forecast for the location"
+//	@Failure	    500		{object}	models.Problem
+//	@Failure	    502		{object}	models.Problem	"NWS returned an error or an unreadable response"
+//	@Failure	    503		{object}	models.Problem	"NWS is down, throttling requests or has no forecast data right now"
+//	@Failure	    504		{object}	models.Problem	"NWS did not answer in time"
+//	@Router			/v1/forecasts/{latitude}/{longitude}/periods [get]
+func GetForecastPeriods(client services.WeatherClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		coordinate, err := coordinateParams(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		units, err := unitsParam(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		periods, err := client.GetForecastPeriods(r.Context(), coordinate, units)
+
+		if err != nil {
+			utils.ProblemResponse(w, clientProblem(r, err))
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json; charset=utf-8")
+		staleHeaders(w, periods.Stale, periods.AgeSeconds)
+		utils.JSONResponse(w, periods)
+	}
+}
+
+// GetHourlyForecast
+//
+//	@Summary		Returns the hourly forecast by latitude and longitude coordinates
+//	@Description	Get the hour by hour forecast, starting with the current hour, by coordinates
+//	@ID				get-hourly-forecast-by-coordinates
+//	@Produce		json,application/problem+json
+//	@Param			latitude	 path	    number true	"The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals" Format(float) minimum(-90) maximum(90)
+//	@Param			longitude	 path	    number true	"The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals" Format(float) minimum(-180) maximum(180)
+//	@Param			hours		 query	    int false	"How many hours to return; all that NWS forecasts by default" minimum(1) maximum(156)
+//	@Param			start		 query	    string false	"RFC 3339 time of the first hour to return (e.g. 2024-03-01T15:00:00-06:00); now by default" Format(date-time)
+//	@Param			units		 query	    string false	"Unit system of the response; the server's default, us unless configured otherwise, when not given" Enums(us, si, metric)
+//	@Success		200		{object}	models.HourlyForecast
+//	@Header			200		{integer}	Age		"Seconds since a stale forecast was fetched from NWS"
+//	@Header			200		{string}	Warning	"Warning code 110 (Response is Stale) when a stale forecast is served"
+//	@Failure	    400		{object}	models.Problem	"The coordinates or query parameters are invalid"
+//	@Failure	    404		{object}	models.Problem	"NWS has no hourly forecast for the location"
+//	@Failure	    500		{object}	models.Problem
+//	@Failure	    502		{object}	models.Problem	"NWS returned an error or an unreadable response"
+//	@Failure	    503		{object}	models.Problem	"NWS is down, throttling requests or has no forecast data right now"
+//	@Failure	    504		{object}	models.Problem	"NWS did not answer in time"
+//	@Router			/v1/forecasts/{latitude}/{longitude}/hourly [get]
+func GetHourlyForecast(client services.WeatherClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		coordinate, err := coordinateParams(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		units, err := unitsParam(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		query, err := hourlyQuery(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		hourly, err := client.GetHourlyForecast(r.Context(), coordinate, units, query)
+
+		if err != nil {
+			utils.ProblemResponse(w, clientProblem(r, err))
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json; charset=utf-8")
+		staleHeaders(w, hourly.Stale, hourly.AgeSeconds)
+		utils.JSONResponse(w, hourly)
+	}
+}
+
+// hourlyQuery parses the hours and start query parameters.
+func hourlyQuery(r *http.Request) (models.HourlyQuery, error) {
+	var query models.HourlyQuery
+
+	if value := r.URL.Query().Get("hours"); value != "" {
+		hours, err := strconv.Atoi(value)
+
+		if err != nil || hours < 1 || hours > models.MaxHourlyHours {
+			return query, fmt.Errorf("invalid hours %q: must be a whole number between 1 and %d", value, models.MaxHourlyHours)
+		}
+
+		query.Hours = hours
+	}
+
+	if value := r.URL.Query().Get("start"); value != "" {
+		start, err := time.Parse(time.RFC3339, value)
+
+		if err != nil {
+			return query, fmt.Errorf("invalid start %q: must be an RFC 3339 time such as 2024-03-01T15:00:00-06:00", value)
+		}
+
+		query.Start = start
+	}
+
+	return query, nil
+}
+
+// GetDailyForecast
+//
+//	@Summary		Returns the forecast per day by latitude and longitude coordinates
+//	@Description	Get one summary per local calendar day with the high, low, dominant condition, highest probability of precipitation and the day and night forecasts
+//	@ID				get-daily-forecast-by-coordinates
+//	@Produce		json,application/problem+json
+//	@Param			latitude	 path	    number true	"The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals" Format(float) minimum(-90) maximum(90)
+//	@Param			longitude	 path	    number true	"The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals" Format(float) minimum(-180) maximum(180)
+//	@Param			units		 query	    string false	"Unit system of the response; the server's default, us unless configured otherwise, when not given" Enums(us, si, metric)
+//	@Success		200		{object}	models.DailyForecast
+//	@Header			200		{integer}	Age		"Seconds since a stale forecast was fetched from NWS"
+//	@Header			200		{string}	Warning	"Warning code 110 (Response is Stale) when a stale forecast is served"
+//	@Failure	    400		{object}	models.Problem	"The coordinates or query parameters are invalid"
+//	@Failure	    404		{object}	models.Problem	"NWS has no forecast for the location"
+//	@Failure	    500		{object}	models.Problem
+//	@Failure	    502		{object}	models.Problem	"NWS returned an error or an unreadable response"
+//	@Failure	    503		{object}	models.Problem	"NWS is down, throttling requests or has no forecast data right now"
+//	@Failure	    504		{object}	models.Problem	"NWS did not answer in time"
+//	@Router			/v1/forecasts/{latitude}/{longitude}/daily [get]
+func GetDailyForecast(client services.WeatherClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		coordinate, err := coordinateParams(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		units, err := unitsParam(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		daily, err := client.GetDailyForecast(r.Context(), coordinate, units)
+
+		if err != nil {
+			utils.ProblemResponse(w, clientProblem(r, err))
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json; charset=utf-8")
+		staleHeaders(w, daily.Stale, daily.AgeSeconds)
+		utils.JSONResponse(w, daily)
+	}
+}
+
+// GetAlerts
+//
+//	@Summary		Returns the active weather alerts by latitude and longitude coordinates
+//	@Description	Get the NWS watches, warnings and advisories in effect at the coordinates
+//	@ID				get-alerts-by-coordinates
+//	@Produce		json,application/problem+json
+//	@Param			latitude	 path	    number true	"The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals" Format(float) minimum(-90) maximum(90)
+//	@Param			longitude	 path	    number true	"The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals" Format(float) minimum(-180) maximum(180)
+//	@Param			severity	 query	    []string false	"Only return alerts with one of these severities" collectionFormat(csv) Enums(Extreme, Severe, Moderate, Minor, Unknown)
+//	@Param			event		 query	    []string false	"Only return these events (e.g. Tornado Warning)" collectionFormat(csv)
+//	@Success		200		{object}	models.Alerts
+//	@Header			200		{integer}	Age		"Seconds since stale alerts were fetched from NWS"
+//	@Header			200		{string}	Warning	"Warning code 110 (Response is Stale) when stale alerts are served"
+//	@Failure	    400		{object}	models.Problem	"The coordinates or query parameters are invalid"
+//	@Failure	    500		{object}	models.Problem
+//	@Failure	    502		{object}	models.Problem	"NWS returned an error or an unreadable response"
+//	@Failure	    503		{object}	models.Problem	"NWS is down or throttling requests"
+//	@Failure	    504		{object}	models.Problem	"NWS did not answer in time"
+//	@Router			/v1/alerts/{latitude}/{longitude} [get]
+func GetAlerts(client services.WeatherClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		coordinate, err := coordinateParams(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		query, err := alertQuery(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		alerts, err := client.GetAlerts(r.Context(), coordinate, query)
+
+		if err != nil {
+			utils.ProblemResponse(w, clientProblem(r, err))
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json; charset=utf-8")
+		staleHeaders(w, alerts.Stale, alerts.AgeSeconds)
+		utils.JSONResponse(w, alerts)
+	}
+}
+
+// alertQuery parses the severity and event query parameters, each given
+// as a comma separated list or repeated.
+func alertQuery(r *http.Request) (models.AlertQuery, error) {
+	query := models.AlertQuery{
+		Severities: listParam(r, "severity"),
+		Events:     listParam(r, "event"),
+	}
+
+	for _, severity := range query.Severities {
+		if !slices.ContainsFunc(models.AlertSeverities, func(s string) bool { return strings.EqualFold(s, severity) }) {
+			return query, fmt.Errorf("invalid severity %q: must be one of %s", severity, strings.Join(models.AlertSeverities, ", "))
+		}
+	}
+
+	return query, nil
+}
+
+func listParam(r *http.Request, name string) []string {
+	var values []string
+
+	for _, param := range r.URL.Query()[name] {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
+
+// GetConditions
+//
+//	@Summary		Returns the current observed conditions by latitude and longitude coordinates
+//	@Description	Get the latest observation from the nearest NWS station with a recent one
+//	@ID				get-conditions-by-coordinates
+//	@Produce		json,application/problem+json
+//	@Param			latitude	 path	    number true	"The latitude of the desired location  (e.g. 39.7456), rounded to 4 decimals" Format(float) minimum(-90) maximum(90)
+//	@Param			longitude	 path	    number true	"The longitude of the desired location  (e.g. -97.0892), rounded to 4 decimals" Format(float) minimum(-180) maximum(180)
+//	@Param			units		 query	    string false	"Unit system of the response; the server's default, us unless configured otherwise, when not given" Enums(us, si, metric)
+//	@Success		200		{object}	models.Conditions
+//	@Header			200		{integer}	Age		"Seconds since a stale observation was fetched from NWS"
+//	@Header			200		{string}	Warning	"Warning code 110 (Response is Stale) when a stale observation is served"
+//	@Failure	    400		{object}	models.Problem	"The coordinates or query parameters are invalid"
+//	@Failure	    404		{object}	models.Problem	"NWS has no data for the location"
+//	@Failure	    500		{object}	models.Problem
+//	@Failure	    502		{object}	models.Problem	"NWS returned an error or an unreadable response"
+//	@Failure	    503		{object}	models.Problem	"NWS is down, throttling requests or no nearby station has a recent observation"
+//	@Failure	    504		{object}	models.Problem	"NWS did not answer in time"
+//	@Router			/v1/conditions/{latitude}/{longitude} [get]
+func GetConditions(client services.WeatherClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		coordinate, err := coordinateParams(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		units, err := unitsParam(r)
+
+		if err != nil {
+			utils.ProblemResponse(w, newProblem(r, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		conditions, err := client.GetConditions(r.Context(), coordinate, units)
+
+		if err != nil {
+			utils.ProblemResponse(w, clientProblem(r, err))
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json; charset=utf-8")
+		staleHeaders(w, conditions.Stale, conditions.AgeSeconds)
+		utils.JSONResponse(w, conditions)
+	}
+}
+
+// unitsParam parses the units query parameter. It is empty, leaving the
+// choice to the client's default, when not given.
+func unitsParam(r *http.Request) (models.UnitSystem, error) {
+	value := r.URL.Query().Get("units")
+
+	if value == "" {
+		return "", nil
+	}
+
+	return models.ParseUnitSystem(value)
+}
+
+func coordinateParams(r *http.Request) (models.Coordinate, error) {
+	return models.ParseCoordinate(chi.URLParam(r, "latitude"), chi.URLParam(r, "longitude"))
+}
+
+// staleHeaders marks a response served from the cache after it expired.
+func staleHeaders(w http.ResponseWriter, stale bool, ageSeconds int) {
+	if stale {
+		w.Header().Set("Age", strconv.Itoa(ageSeconds))
+		w.Header().Set("Warning", `110 - "Response is Stale"`)
+	}
+}
+
+// newProblem returns a problem for the request r, carrying its request ID.
+func newProblem(r *http.Request, status int, detail string) models.Problem {
+	problem := models.NewProblem(status, detail)
+	problem.Instance = r.URL.Path
+	problem.RequestID = middleware.GetReqID(r.Context())
+
+	return problem
+}
+
+// clientProblem describes an error from the weather client, passing on the
+// correlation ID NWS gave a failed request.
+func clientProblem(r *http.Request, err error) models.Problem {
+	problem := newProblem(r, errorStatus(err), err.Error())
+
+	if problem.Status == statusClientClosedRequest {
+		problem.Title = "Client Closed Request"
 	}
 
-	utils.JSONResponse(w, forecast)
+	var statusErr *services.UpstreamStatusError
+	if errors.As(err, &statusErr) {
+		problem.CorrelationID = statusErr.CorrelationID
+	}
+
+	return problem
+}
+
+// NotFound answers requests for routes that do not exist.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	utils.ProblemResponse(w, newProblem(r, http.StatusNotFound, fmt.Sprintf("no route for %s", r.URL.Path)))
+}
+
+// MethodNotAllowed answers requests for a route with a method it does not
+// support. chi does not pass a custom handler the allowed methods, so they
+// are looked up again for the Allow header.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.Routes != nil {
+		for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions} {
+			if rctx.Routes.Match(chi.NewRouteContext(), method, r.URL.Path) {
+				w.Header().Add("Allow", method)
+			}
+		}
+	}
+
+	utils.ProblemResponse(w, newProblem(r, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed for %s", r.Method, r.URL.Path)))
+}
+
+// statusClientClosedRequest is the non-standard status nginx logs for a
+// client that went away before it was answered, keeping disconnects apart
+// from NWS timeouts.
+const statusClientClosedRequest = 499
+
+// errorStatus maps an error from the weather client to the status we
+// answer with. Failures of NWS map to 502, 503 or 504; 500 is left for
+// errors that are our own fault.
+func errorStatus(err error) int {
+	var statusErr *services.UpstreamStatusError
+	var networkErr *services.NetworkError
+	var decodeErr *services.DecodeError
+	var unavailableErr *services.DataUnavailableError
+	var linkErr *services.LinkNotAllowedError
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest
+	case errors.Is(err, services.ErrMissingLink):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrCircuitOpen), errors.Is(err, services.ErrRateLimited), errors.As(err, &unavailableErr):
+		return http.StatusServiceUnavailable
+	case errors.As(err, &statusErr):
+		return upstreamStatus(statusErr.StatusCode)
+	case errors.As(err, &networkErr), errors.As(err, &decodeErr), errors.As(err, &linkErr):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// upstreamStatus maps a status returned by NWS to the status we answer
+// with. NWS rejects coordinates it cannot parse with 400 and ones outside
+// its coverage with 404, which are the caller's to fix; anything else is
+// a failure of NWS.
+func upstreamStatus(code int) int {
+	switch code {
+	case http.StatusBadRequest, http.StatusNotFound:
+		return code
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return http.StatusServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
 }
 
 func RedirectRootToSwagger(w http.ResponseWriter, r *http.Request) {
@@ -52,15 +556,25 @@ func SwaggerHandler() http.HandlerFunc {
 	)
 }
 
-func GetRouter() *chi.Mux {
+// GetRouter serves every request with client, so its response cache is
+// shared across requests.
+func GetRouter(client services.WeatherClient) *chi.Mux {
 	router := chi.NewRouter()
+	router.Use(middleware.RequestID)
 	router.Use(middleware.Logger)
+	router.NotFound(NotFound)
+	router.MethodNotAllowed(MethodNotAllowed)
 
 	// Redirect root to swagger docs
 	router.Get("/", RedirectRootToSwagger)
 
 	router.Route("/v1", func(r chi.Router) {
-		r.Get("/forecasts/{latitude}/{longitude}", GetForecast)
+		r.Get("/forecasts/{latitude}/{longitude}", GetForecast(client))
+		r.Get("/forecasts/{latitude}/{longitude}/periods", GetForecastPeriods(client))
+		r.Get("/forecasts/{latitude}/{longitude}/hourly", GetHourlyForecast(client))
+		r.Get("/forecasts/{latitude}/{longitude}/daily", GetDailyForecast(client))
+		r.Get("/alerts/{latitude}/{longitude}", GetAlerts(client))
+		r.Get("/conditions/{latitude}/{longitude}", GetConditions(client))
 	})
 
 	router.Get("/swagger/*", SwaggerHandler())
@@ -78,7 +592,49 @@ func GetRouter() *chi.Mux {
 // @host localhost:8080
 // @BasePath /
 func main() {
-	router := GetRouter()
+	opts := []services.Option{
+		services.WithStaleWhileRevalidate(5 * time.Minute),
+		services.WithStaleIfError(6 * time.Hour),
+	}
+
+	// NWS asks every client to identify itself and how to contact its operator
+	if userAgent := os.Getenv("WEATHER_API_USER_AGENT"); userAgent != "" {
+		opts = append(opts, services.WithUserAgent(userAgent))
+	}
+
+	// Point at a staging mirror or stand-in server instead of NWS itself
+	if baseURL := os.Getenv("WEATHER_API_NWS_BASE_URL"); baseURL != "" {
+		opts = append(opts, services.WithBaseURL(baseURL))
+	}
+
+	// Origins a mirror may link to besides its own, e.g. https://api.weather.gov
+	if origins := os.Getenv("WEATHER_API_NWS_ALLOWED_ORIGINS"); origins != "" {
+		opts = append(opts, services.WithAllowedOrigins(strings.Split(origins, ",")...))
+	}
+
+	// Unit system of responses that do not ask for one: us, si or metric
+	if value := os.Getenv("WEATHER_API_UNITS"); value != "" {
+		units, err := models.ParseUnitSystem(value)
+
+		if err != nil {
+			log.Fatalf("WEATHER_API_UNITS: %v", err)
+		}
+
+		opts = append(opts, services.WithUnits(units))
+	}
+
+	// Persist cached NWS responses across restarts when a cache file is set
+	if path := os.Getenv("WEATHER_API_CACHE_FILE"); path != "" {
+		cache, err := services.NewFileCache(path, services.DefaultCacheMaxEntries)
+
+		if err != nil {
+			log.Fatalf("unable to open cache file %s: %v", path, err)
+		}
+
+		opts = append(opts, services.WithCache(cache))
+	}
+
+	router := GetRouter(services.NewClient(opts...))
 
 	log.Println("Go to http://localhost:8080")
 
@@ -1,17 +1,26 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rmccullagh/weather-api/models"
+	"github.com/rmccullagh/weather-api/services"
 )
 
 func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
 
 func TestGetForecast_ErrorConditions(t *testing.T) {
+	t.Parallel()
+
 	tests := []struct {
 		name       string
 		transport  http.RoundTripper
@@ -26,7 +35,7 @@ func TestGetForecast_ErrorConditions(t *testing.T) {
 				}
 				return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(`{"detail":"not used"}`)), Header: make(http.Header)}, nil
 			}),
-			wantStatus: http.StatusInternalServerError,
+			wantStatus: http.StatusBadGateway,
 			wantBody:   "network fail",
 		},
 		{
@@ -37,7 +46,7 @@ func TestGetForecast_ErrorConditions(t *testing.T) {
 				}
 				return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(`{"detail":"not used"}`)), Header: make(http.Header)}, nil
 			}),
-			wantStatus: http.StatusInternalServerError,
+			wantStatus: http.StatusBadRequest,
 			wantBody:   "bad point",
 		},
 		{
@@ -53,18 +62,61 @@ func TestGetForecast_ErrorConditions(t *testing.T) {
 					return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(`{"detail":"not found"}`)), Header: make(http.Header)}, nil
 				}
 			}),
-			wantStatus: http.StatusInternalServerError,
+			wantStatus: http.StatusBadGateway,
 			wantBody:   "forecast network fail",
 		},
+		{
+			name: "point outside coverage",
+			transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				body := `{"title":"Data Unavailable For Requested Point","detail":"Unable to provide data for requested point 1,2"}`
+				return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+			}),
+			wantStatus: http.StatusNotFound,
+			wantBody:   "Unable to provide data",
+		},
+		{
+			name: "forecast upstream server error",
+			transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				switch req.URL.Path {
+				case "/points/1,2":
+					body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+					return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+				default:
+					return &http.Response{StatusCode: 500, Body: io.NopCloser(strings.NewReader(`{"detail":"unexpected problem"}`)), Header: make(http.Header)}, nil
+				}
+			}),
+			wantStatus: http.StatusBadGateway,
+			wantBody:   "unexpected problem",
+		},
+		{
+			name: "forecast upstream unavailable",
+			transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: 503, Body: io.NopCloser(strings.NewReader(`{"detail":"maintenance"}`)), Header: make(http.Header)}, nil
+			}),
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "maintenance",
+		},
+		{
+			name: "forecast malformed JSON",
+			transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				switch req.URL.Path {
+				case "/points/1,2":
+					body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+					return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+				default:
+					return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`{"properties":`)), Header: make(http.Header)}, nil
+				}
+			}),
+			wantStatus: http.StatusBadGateway,
+			wantBody:   "unable to decode response",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			orig := http.DefaultTransport
-			http.DefaultTransport = tc.transport
-			defer func() { http.DefaultTransport = orig }()
+			t.Parallel()
 
-			router := GetRouter()
+			router := GetRouter(services.NewClient(services.WithTransport(tc.transport)))
 
 			req := httptest.NewRequest("GET", "/v1/forecasts/1/2", nil)
 			rr := httptest.NewRecorder()
@@ -79,3 +131,131 @@ func TestGetForecast_ErrorConditions(t *testing.T) {
 		})
 	}
 }
+
+func TestGetForecast_ClientErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"circuit open", services.ErrCircuitOpen, http.StatusServiceUnavailable},
+		{"rate limited", services.ErrRateLimited, http.StatusServiceUnavailable},
+		{"overall timeout", context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"attempt timeout", &services.NetworkError{URL: "u", Err: fmt.Errorf("get: %w", context.DeadlineExceeded)}, http.StatusGatewayTimeout},
+		{"client went away", context.Canceled, statusClientClosedRequest},
+		{"network", &services.NetworkError{URL: "u", Err: errors.New("reset")}, http.StatusBadGateway},
+		{"decode", &services.DecodeError{URL: "u", Err: errors.New("bad json")}, http.StatusBadGateway},
+		{"upstream bad request", &services.UpstreamStatusError{StatusCode: 400}, http.StatusBadRequest},
+		{"upstream not found", &services.UpstreamStatusError{StatusCode: 404}, http.StatusNotFound},
+		{"upstream forbidden", &services.UpstreamStatusError{StatusCode: 403}, http.StatusBadGateway},
+		{"upstream throttling", &services.UpstreamStatusError{StatusCode: 429}, http.StatusServiceUnavailable},
+		{"upstream server error", &services.UpstreamStatusError{StatusCode: 500}, http.StatusBadGateway},
+		{"upstream unavailable", &services.UpstreamStatusError{StatusCode: 503}, http.StatusServiceUnavailable},
+		{"upstream timeout", &services.UpstreamStatusError{StatusCode: 504}, http.StatusGatewayTimeout},
+		{"link leaves NWS", &services.LinkNotAllowedError{URL: "http://169.254.169.254/"}, http.StatusBadGateway},
+		{"no forecast periods", &services.DataUnavailableError{URL: "u", Err: models.ErrNoPeriods}, http.StatusServiceUnavailable},
+		{"no hourly forecast", &services.DataUnavailableError{URL: "u", Err: fmt.Errorf("%w: forecastHourly", services.ErrMissingLink)}, http.StatusNotFound},
+		{"our bug", errors.New("nil pointer"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := chi.NewRouter()
+			router.Get("/v1/forecasts/{latitude}/{longitude}", GetForecast(stubClient{err: tc.err}))
+
+			req := httptest.NewRequest("GET", "/v1/forecasts/1/2", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("status: got %d want %d", rr.Code, tc.wantStatus)
+			}
+			if !strings.Contains(rr.Body.String(), tc.err.Error()) {
+				t.Fatalf("body: expected to contain %q, got %s", tc.err.Error(), rr.Body.String())
+			}
+			var problem models.Problem
+			if err := json.Unmarshal(rr.Body.Bytes(), &problem); err != nil || problem.Title == "" || problem.Status != tc.wantStatus {
+				t.Fatalf("body: expected a titled problem, got %s", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetForecast_InvalidCoordinates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		path     string
+		wantBody string
+	}{
+		{"latitude not a number", "/v1/forecasts/abc/2", "invalid latitude"},
+		{"longitude not a number", "/v1/forecasts/1/xyz", "invalid longitude"},
+		{"latitude out of range", "/v1/forecasts/91/2", "invalid latitude"},
+		{"longitude out of range", "/v1/forecasts/1/-180.5", "invalid longitude"},
+		{"not a finite number", "/v1/forecasts/NaN/Inf", "invalid latitude"},
+		{"whitespace", "/v1/forecasts/%201/2", "invalid latitude"},
+		{"encoded path segments", "/v1/forecasts/1/2%2F..%2Falerts", "invalid longitude"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := GetRouter(stubClient{err: errors.New("client should not be called")})
+
+			req := httptest.NewRequest("GET", tc.path, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("status: got %d want %d", rr.Code, http.StatusBadRequest)
+			}
+			if !strings.Contains(rr.Body.String(), tc.wantBody) {
+				t.Fatalf("body: expected to contain %q, got %s", tc.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetForecast_ProblemDocument(t *testing.T) {
+	t.Parallel()
+
+	router := GetRouter(stubClient{err: &services.UpstreamStatusError{
+		URL:           "https://api.weather.gov/points/1,2",
+		StatusCode:    500,
+		Detail:        "Unexpected Problem",
+		CorrelationID: "abc123",
+	}})
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2", nil)
+	req.Header.Set("X-Request-Id", "req-42")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("content type: got %q", ct)
+	}
+
+	var problem models.Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+
+	want := models.Problem{
+		Type:          "about:blank",
+		Title:         "Bad Gateway",
+		Status:        http.StatusBadGateway,
+		Detail:        "Unexpected Problem",
+		Instance:      "/v1/forecasts/1/2",
+		RequestID:     "req-42",
+		CorrelationID: "abc123",
+	}
+	if problem != want {
+		t.Fatalf("problem: got %+v want %+v", problem, want)
+	}
+}
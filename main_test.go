@@ -1,20 +1,28 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rmccullagh/weather-api/models"
+	"github.com/rmccullagh/weather-api/services"
 )
 
 type roundTripperFunc func(*http.Request) (*http.Response, error)
 
 func TestGetForecast_Success(t *testing.T) {
-	orig := http.DefaultTransport
-	defer func() { http.DefaultTransport = orig }()
+	t.Parallel()
 
-	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		switch req.URL.Path {
 		case "/points/1,2":
 			body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
@@ -27,7 +35,7 @@ func TestGetForecast_Success(t *testing.T) {
 		}
 	})
 
-	router := GetRouter()
+	router := GetRouter(services.NewClient(services.WithTransport(transport)))
 
 	req := httptest.NewRequest("GET", "/v1/forecasts/1/2", nil)
 	rr := httptest.NewRecorder()
@@ -44,7 +52,9 @@ func TestGetForecast_Success(t *testing.T) {
 }
 
 func TestRootRedirect(t *testing.T) {
-	router := GetRouter()
+	t.Parallel()
+
+	router := GetRouter(services.NewClient())
 
 	req := httptest.NewRequest("GET", "/", nil)
 	rr := httptest.NewRecorder()
@@ -59,7 +69,9 @@ func TestRootRedirect(t *testing.T) {
 }
 
 func TestSwaggerHandler(t *testing.T) {
-	router := GetRouter()
+	t.Parallel()
+
+	router := GetRouter(services.NewClient())
 	req := httptest.NewRequest("GET", "/swagger/", nil)
 	rr := httptest.NewRecorder()
 
@@ -69,3 +81,807 @@ func TestSwaggerHandler(t *testing.T) {
 		t.Fatalf("unexpected status code: %d", rr.Code)
 	}
 }
+
+type stubClient struct {
+	forecast *models.Forecast
+	periods  *models.ForecastPeriods
+	hourly   *models.HourlyForecast
+	query    *models.HourlyQuery
+	daily    *models.DailyForecast
+	alerts   *models.Alerts
+	alertQ   *models.AlertQuery
+	alertErr error
+	conds    *models.Conditions
+	units    *models.UnitSystem
+	err      error
+	ctx      *context.Context
+}
+
+func (s stubClient) GetForecast(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.Forecast, error) {
+	if s.ctx != nil {
+		*s.ctx = ctx
+	}
+	if s.units != nil {
+		*s.units = units
+	}
+	return s.forecast, s.err
+}
+
+func (s stubClient) GetForecastPeriods(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.ForecastPeriods, error) {
+	if s.ctx != nil {
+		*s.ctx = ctx
+	}
+	if s.units != nil {
+		*s.units = units
+	}
+	return s.periods, s.err
+}
+
+func (s stubClient) GetHourlyForecast(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem, query models.HourlyQuery) (*models.HourlyForecast, error) {
+	if s.ctx != nil {
+		*s.ctx = ctx
+	}
+	if s.units != nil {
+		*s.units = units
+	}
+	if s.query != nil {
+		*s.query = query
+	}
+	return s.hourly, s.err
+}
+
+func (s stubClient) GetDailyForecast(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.DailyForecast, error) {
+	if s.ctx != nil {
+		*s.ctx = ctx
+	}
+	if s.units != nil {
+		*s.units = units
+	}
+	return s.daily, s.err
+}
+
+func (s stubClient) GetConditions(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.Conditions, error) {
+	if s.ctx != nil {
+		*s.ctx = ctx
+	}
+	if s.units != nil {
+		*s.units = units
+	}
+	return s.conds, s.err
+}
+
+func (s stubClient) GetAlerts(ctx context.Context, coordinate models.Coordinate, query models.AlertQuery) (*models.Alerts, error) {
+	if s.ctx != nil {
+		*s.ctx = ctx
+	}
+	if s.alertQ != nil {
+		*s.alertQ = query
+	}
+	if s.alertErr != nil {
+		return nil, s.alertErr
+	}
+	return s.alerts, s.err
+}
+
+type ctxKey struct{}
+
+func TestGetForecast_PassesRequestContext(t *testing.T) {
+	t.Parallel()
+
+	var got context.Context
+	router := chi.NewRouter()
+	router.Get("/v1/forecasts/{latitude}/{longitude}", GetForecast(stubClient{
+		forecast: &models.Forecast{},
+		ctx:      &got,
+	}))
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ctxKey{}, "marker"))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got == nil || got.Value(ctxKey{}) != "marker" {
+		t.Fatal("expected handler to pass the request context to the client")
+	}
+}
+
+func TestGetForecast_StaleHeaders(t *testing.T) {
+	t.Parallel()
+
+	router := chi.NewRouter()
+	router.Get("/v1/forecasts/{latitude}/{longitude}", GetForecast(stubClient{
+		forecast: &models.Forecast{ForecastDaily: "Sunny", Temperature: 90, Freshness: models.Freshness{Stale: true, AgeSeconds: 120}},
+	}))
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d want %d", rr.Code, http.StatusOK)
+	}
+	if got := rr.Header().Get("Age"); got != "120" {
+		t.Fatalf("Age header: got %q want %q", got, "120")
+	}
+	if got := rr.Header().Get("Warning"); !strings.Contains(got, "Response is Stale") {
+		t.Fatalf("Warning header: got %q", got)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, `"stale": true`) || !strings.Contains(body, `"age_seconds": 120`) {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
+
+func TestGetForecast_FreshHasNoStaleHeaders(t *testing.T) {
+	t.Parallel()
+
+	router := chi.NewRouter()
+	router.Get("/v1/forecasts/{latitude}/{longitude}", GetForecast(stubClient{
+		forecast: &models.Forecast{ForecastDaily: "Sunny", Temperature: 90},
+	}))
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Header().Get("Age") != "" || rr.Header().Get("Warning") != "" {
+		t.Fatalf("unexpected stale headers: %v", rr.Header())
+	}
+	if strings.Contains(rr.Body.String(), "stale") {
+		t.Fatalf("unexpected stale field in body: %s", rr.Body.String())
+	}
+}
+
+func TestRouter_UnknownRouteIsProblem(t *testing.T) {
+	t.Parallel()
+
+	router := GetRouter(stubClient{})
+
+	req := httptest.NewRequest("GET", "/v1/nowhere", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("status: got %d want %d", rr.Code, http.StatusNotFound)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("content type: got %q", ct)
+	}
+
+	var problem models.Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Status != http.StatusNotFound || problem.Title != "Not Found" || problem.Instance != "/v1/nowhere" {
+		t.Fatalf("unexpected problem: %+v", problem)
+	}
+	if problem.RequestID == "" {
+		t.Fatal("expected a request ID")
+	}
+}
+
+func TestRouter_WrongMethodIsProblem(t *testing.T) {
+	t.Parallel()
+
+	router := GetRouter(stubClient{})
+
+	req := httptest.NewRequest("POST", "/v1/forecasts/1/2", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status: got %d want %d", rr.Code, http.StatusMethodNotAllowed)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("content type: got %q", ct)
+	}
+	if allow := rr.Header().Values("Allow"); len(allow) != 1 || allow[0] != http.MethodGet {
+		t.Fatalf("allow: got %v", allow)
+	}
+}
+
+func TestGetForecast_NormalizesCoordinates(t *testing.T) {
+	t.Parallel()
+
+	var requested string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(req.URL.Path, "/points/") {
+			requested = req.URL.Path
+			body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}
+		body := `{"properties":{"periods":[{"shortForecast":"Sunny","temperature":90}]}}`
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	router := GetRouter(services.NewClient(services.WithTransport(transport)))
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/39.745600/-97.08925", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if requested != "/points/39.7456,-97.0893" {
+		t.Fatalf("requested %q", requested)
+	}
+}
+
+func TestGetForecastPeriods_Success(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+		if req.URL.Path == "/forecast/1" {
+			body = `{"properties":{"periods":[
+				{"number":1,"name":"Today","isDaytime":true,"temperature":90,"temperatureUnit":"F","probabilityOfPrecipitation":{"unitCode":"wmoUnit:percent","value":10},"windSpeed":"10 mph","windDirection":"S","shortForecast":"Sunny","detailedForecast":"Sunny and hot."},
+				{"number":2,"name":"Tonight","isDaytime":false,"temperature":45,"temperatureUnit":"F","shortForecast":"Clear"}
+			]}}`
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	router := GetRouter(services.NewClient(services.WithTransport(transport)))
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2/periods", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var got models.ForecastPeriods
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got.Periods) != 2 {
+		t.Fatalf("expected 2 periods, got %+v", got)
+	}
+
+	today := got.Periods[0]
+	if today.Name != "Today" || today.Characterization != models.Hot || today.WindSpeed != "10 mph" || today.ProbabilityOfPrecipitation == nil || *today.ProbabilityOfPrecipitation != 10 {
+		t.Fatalf("unexpected period: %+v", today)
+	}
+	if got.Periods[1].Characterization != models.Cold {
+		t.Fatalf("unexpected period: %+v", got.Periods[1])
+	}
+}
+
+func TestGetForecastPeriods_Errors(t *testing.T) {
+	t.Parallel()
+
+	router := GetRouter(stubClient{err: services.ErrCircuitOpen})
+
+	for path, want := range map[string]int{
+		"/v1/forecasts/91/2/periods": http.StatusBadRequest,
+		"/v1/forecasts/1/2/periods":  http.StatusServiceUnavailable,
+	} {
+		req := httptest.NewRequest("GET", path, nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != want {
+			t.Fatalf("%s: got %d want %d", path, rr.Code, want)
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+			t.Fatalf("%s: content type %q", path, ct)
+		}
+	}
+}
+
+func TestGetForecastPeriods_StaleHeaders(t *testing.T) {
+	t.Parallel()
+
+	router := GetRouter(stubClient{periods: &models.ForecastPeriods{Freshness: models.Freshness{Stale: true, AgeSeconds: 60}}})
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2/periods", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Header().Get("Age") != "60" || rr.Header().Get("Warning") == "" {
+		t.Fatalf("expected stale headers, got %v", rr.Header())
+	}
+}
+
+func TestGetHourlyForecast_Success(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1","forecastHourly":"https://api.weather.gov/forecast/1/hourly"}}`
+		if req.URL.Path == "/forecast/1/hourly" {
+			body = `{"properties":{"periods":[
+				{"number":1,"startTime":"2024-03-01T14:00:00-06:00","endTime":"2024-03-01T15:00:00-06:00","isDaytime":true,"temperature":61,"temperatureUnit":"F","dewpoint":{"unitCode":"wmoUnit:degC","value":10},"relativeHumidity":{"unitCode":"wmoUnit:percent","value":45},"probabilityOfPrecipitation":{"unitCode":"wmoUnit:percent","value":0},"windSpeed":"10 mph","windDirection":"SW","shortForecast":"Sunny"},
+				{"number":2,"startTime":"2024-03-01T15:00:00-06:00","endTime":"2024-03-01T16:00:00-06:00","isDaytime":true,"temperature":62,"temperatureUnit":"F","shortForecast":"Sunny"},
+				{"number":3,"startTime":"2024-03-01T16:00:00-06:00","endTime":"2024-03-01T17:00:00-06:00","isDaytime":true,"temperature":60,"temperatureUnit":"F","shortForecast":"Mostly Sunny"},
+				{"number":4,"startTime":"2024-03-01T17:00:00-06:00","endTime":"2024-03-01T18:00:00-06:00","isDaytime":true,"temperature":57,"temperatureUnit":"F","shortForecast":"Mostly Sunny"}
+			]}}`
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	router := GetRouter(services.NewClient(services.WithTransport(transport)))
+
+	tests := []struct {
+		name      string
+		query     string
+		wantTemps []int
+	}{
+		{"from the hour in effect at start", "?start=2024-03-01T14:30:00-06:00", []int{61, 62, 60, 57}},
+		{"limited number of hours", "?start=2024-03-01T15:00:00-06:00&hours=2", []int{62, 60}},
+		{"start after the forecast", "?start=2024-03-02T00:00:00-06:00", []int{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v1/forecasts/1/2/hourly"+tc.query, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status: got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+			}
+
+			var got models.HourlyForecast
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+
+			temps := []int{}
+			for _, h := range got.Hours {
+				temps = append(temps, h.Temperature)
+			}
+			if fmt.Sprint(temps) != fmt.Sprint(tc.wantTemps) {
+				t.Fatalf("temperatures: got %v want %v", temps, tc.wantTemps)
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2/hourly?start=2024-03-01T14:00:00-06:00&hours=1", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	var got models.HourlyForecast
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	first := got.Hours[0]
+	if *first.Dewpoint != 50 || *first.RelativeHumidity != 45 || *first.ProbabilityOfPrecipitation != 0 || first.WindSpeed != "10 mph" || first.ShortForecast != "Sunny" {
+		t.Fatalf("unexpected hour: %+v", first)
+	}
+}
+
+func TestGetHourlyForecast_InvalidQuery(t *testing.T) {
+	t.Parallel()
+
+	router := GetRouter(stubClient{err: errors.New("client should not be called")})
+
+	for _, query := range []string{"?hours=0", "?hours=157", "?hours=two", "?start=yesterday", "?start=2024-03-01"} {
+		req := httptest.NewRequest("GET", "/v1/forecasts/1/2/hourly"+query, nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: got %d want %d", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetHourlyForecast_PassesQuery(t *testing.T) {
+	t.Parallel()
+
+	var query models.HourlyQuery
+	router := GetRouter(stubClient{hourly: &models.HourlyForecast{}, query: &query})
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2/hourly?hours=12&start=2024-03-01T15:00:00Z", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d want %d", rr.Code, http.StatusOK)
+	}
+	if query.Hours != 12 || !query.Start.Equal(time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected query: %+v", query)
+	}
+}
+
+func TestGetDailyForecast_Success(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1","forecastHourly":"https://api.weather.gov/forecast/1/hourly","timeZone":"America/Chicago"}}`
+		switch req.URL.Path {
+		case "/forecast/1":
+			body = `{"properties":{"periods":[
+				{"number":1,"name":"Saturday","startTime":"2024-03-02T06:00:00-06:00","endTime":"2024-03-02T18:00:00-06:00","isDaytime":true,"temperature":66,"temperatureUnit":"F","shortForecast":"Sunny"},
+				{"number":2,"name":"Saturday Night","startTime":"2024-03-02T18:00:00-06:00","endTime":"2024-03-03T06:00:00-06:00","isDaytime":false,"temperature":45,"temperatureUnit":"F","shortForecast":"Rain","probabilityOfPrecipitation":{"unitCode":"wmoUnit:percent","value":60}}
+			]}}`
+		case "/forecast/1/hourly":
+			return &http.Response{StatusCode: 500, Body: io.NopCloser(strings.NewReader(`{}`)), Header: make(http.Header)}, nil
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	// Without hourly data the summary comes from the periods alone
+	router := GetRouter(services.NewClient(services.WithTransport(transport), services.WithRetryPolicy(services.RetryPolicy{})))
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2/daily", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var got models.DailyForecast
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got.Days) != 1 {
+		t.Fatalf("expected 1 day, got %+v", got.Days)
+	}
+
+	day := got.Days[0]
+	if day.Date != "2024-03-02" || *day.High != 66 || *day.Low != 45 || day.Condition != "Sunny" || *day.ProbabilityOfPrecipitation != 60 {
+		t.Fatalf("unexpected day: %+v", day)
+	}
+	if day.Day.Name != "Saturday" || day.Night.Name != "Saturday Night" {
+		t.Fatalf("unexpected summaries: %+v %+v", day.Day, day.Night)
+	}
+}
+
+func TestGetDailyForecast_Errors(t *testing.T) {
+	t.Parallel()
+
+	router := GetRouter(stubClient{err: context.DeadlineExceeded})
+
+	for path, want := range map[string]int{
+		"/v1/forecasts/1/abc/daily": http.StatusBadRequest,
+		"/v1/forecasts/1/2/daily":   http.StatusGatewayTimeout,
+	} {
+		req := httptest.NewRequest("GET", path, nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != want {
+			t.Fatalf("%s: got %d want %d", path, rr.Code, want)
+		}
+	}
+}
+
+func TestGetAlerts_Success(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"features":[
+			{"properties":{"id":"urn:oid:1","event":"Tornado Warning","severity":"Extreme","certainty":"Observed","urgency":"Immediate","headline":"Tornado Warning issued","description":"A tornado was observed.","instruction":"Take cover now.","effective":"2024-05-01T17:50:00-05:00","expires":"2099-05-01T18:30:00-05:00","areaDesc":"Cook, IL","affectedZones":["https://api.weather.gov/zones/county/ILC031"]}},
+			{"properties":{"id":"urn:oid:2","event":"Wind Advisory","severity":"Minor","expires":"2099-05-01T18:30:00-05:00"}}
+		]}`
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	router := GetRouter(services.NewClient(services.WithTransport(transport)))
+
+	req := httptest.NewRequest("GET", "/v1/alerts/41.8781/-87.6298?severity=extreme,severe", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var got models.Alerts
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got.Alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %+v", got.Alerts)
+	}
+
+	alert := got.Alerts[0]
+	if alert.Event != "Tornado Warning" || alert.Certainty != "Observed" || alert.Urgency != "Immediate" || alert.Instruction != "Take cover now." || alert.AreaDescription != "Cook, IL" || len(alert.AffectedZones) != 1 || alert.Effective.IsZero() {
+		t.Fatalf("unexpected alert: %+v", alert)
+	}
+}
+
+func TestGetAlerts_Query(t *testing.T) {
+	t.Parallel()
+
+	var query models.AlertQuery
+	router := GetRouter(stubClient{alerts: &models.Alerts{}, alertQ: &query})
+
+	req := httptest.NewRequest("GET", "/v1/alerts/1/2?severity=Extreme,%20Severe&event=Tornado+Warning&event=Flood+Warning", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d want %d", rr.Code, http.StatusOK)
+	}
+	if fmt.Sprint(query.Severities) != "[Extreme Severe]" || fmt.Sprint(query.Events) != "[Tornado Warning Flood Warning]" {
+		t.Fatalf("unexpected query: %+v", query)
+	}
+
+	for _, path := range []string{"/v1/alerts/1/2?severity=catastrophic", "/v1/alerts/abc/2"} {
+		req := httptest.NewRequest("GET", path, nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: got %d want %d", path, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+// rendezvousClient only answers once GetForecast and GetAlerts are both in
+// flight, proving that they are called concurrently.
+type rendezvousClient struct {
+	stubClient
+	forecastStarted chan struct{}
+	alertsStarted   chan struct{}
+}
+
+func (c rendezvousClient) GetForecast(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.Forecast, error) {
+	close(c.forecastStarted)
+	select {
+	case <-c.alertsStarted:
+	case <-time.After(5 * time.Second):
+		return nil, errors.New("alerts were not requested concurrently")
+	}
+	return c.stubClient.GetForecast(ctx, coordinate, units)
+}
+
+func (c rendezvousClient) GetAlerts(ctx context.Context, coordinate models.Coordinate, query models.AlertQuery) (*models.Alerts, error) {
+	close(c.alertsStarted)
+	<-c.forecastStarted
+	return c.stubClient.GetAlerts(ctx, coordinate, query)
+}
+
+func TestGetForecast_IncludeAlerts(t *testing.T) {
+	t.Parallel()
+
+	expires := time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC)
+	client := rendezvousClient{
+		stubClient: stubClient{
+			forecast: &models.Forecast{ForecastDaily: "Thunderstorms", Temperature: 80},
+			alerts:   &models.Alerts{Alerts: []models.Alert{{Event: "Tornado Warning", Severity: "Extreme", Headline: "not embedded", Expires: expires}}},
+		},
+		forecastStarted: make(chan struct{}),
+		alertsStarted:   make(chan struct{}),
+	}
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2?include=alerts", nil)
+	rr := httptest.NewRecorder()
+	GetRouter(client).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var got models.Forecast
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	want := []models.AlertSummary{{Event: "Tornado Warning", Severity: "Extreme", Expires: expires}}
+	if fmt.Sprint(got.Alerts) != fmt.Sprint(want) || got.AlertsError != "" || got.ForecastDaily != "Thunderstorms" {
+		t.Fatalf("unexpected forecast: %+v", got)
+	}
+}
+
+func TestGetForecast_IncludeAlertsStale(t *testing.T) {
+	t.Parallel()
+
+	router := GetRouter(stubClient{
+		forecast: &models.Forecast{ForecastDaily: "Sunny"},
+		alerts:   &models.Alerts{Alerts: []models.Alert{}, Freshness: models.Freshness{Stale: true, AgeSeconds: 1800}},
+	})
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2?include=alerts", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d want %d", rr.Code, http.StatusOK)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	// An empty list from a stale response must not read as "no alerts"
+	if got["alerts_stale"] != true || got["alerts_age_seconds"] != float64(1800) || fmt.Sprint(got["alerts"]) != "[]" {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+	if _, ok := got["stale"]; ok {
+		t.Fatalf("forecast marked stale by its alerts: %s", rr.Body.String())
+	}
+}
+
+func TestGetForecast_IncludeAlertsPartialFailure(t *testing.T) {
+	t.Parallel()
+
+	router := GetRouter(stubClient{
+		forecast: &models.Forecast{ForecastDaily: "Sunny"},
+		alertErr: services.ErrCircuitOpen,
+	})
+
+	req := httptest.NewRequest("GET", "/v1/forecasts/1/2?include=alerts", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d want %d", rr.Code, http.StatusOK)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if _, ok := got["alerts"]; ok || got["alerts_error"] != services.ErrCircuitOpen.Error() || got["forecast_daily"] != "Sunny" {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestGetForecast_IncludeParam(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantAlerts string
+	}{
+		{"", http.StatusOK, ""},
+		{"?include=alerts", http.StatusOK, `"alerts": []`},
+		{"?include=radar", http.StatusBadRequest, ""},
+	}
+
+	for _, tc := range tests {
+		router := GetRouter(stubClient{forecast: &models.Forecast{}, alerts: &models.Alerts{}})
+
+		req := httptest.NewRequest("GET", "/v1/forecasts/1/2"+tc.query, nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != tc.wantStatus {
+			t.Fatalf("%q: got %d want %d", tc.query, rr.Code, tc.wantStatus)
+		}
+		if tc.wantStatus == http.StatusOK && strings.Contains(rr.Body.String(), `"alerts"`) != (tc.wantAlerts != "") {
+			t.Fatalf("%q: unexpected body %s", tc.query, rr.Body.String())
+		}
+		if tc.wantAlerts != "" && !strings.Contains(rr.Body.String(), tc.wantAlerts) {
+			t.Fatalf("%q: expected %s in %s", tc.query, tc.wantAlerts, rr.Body.String())
+		}
+	}
+}
+
+func TestGetConditions_Success(t *testing.T) {
+	t.Parallel()
+
+	observed := time.Now().Add(-20 * time.Minute).UTC().Format(time.RFC3339)
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		var body string
+		switch req.URL.Path {
+		case "/points/39.7456,-97.0892":
+			body = `{"properties":{"observationStations":"https://api.weather.gov/gridpoints/TOP/31,80/stations"}}`
+		case "/gridpoints/TOP/31,80/stations":
+			body = `{"features":[
+				{"id":"https://api.weather.gov/stations/FAR","geometry":{"coordinates":[-96.0,39.0]},"properties":{"stationIdentifier":"FAR","name":"Far Away"}},
+				{"id":"https://api.weather.gov/stations/NEAR","geometry":{"coordinates":[-97.09,39.75]},"properties":{"stationIdentifier":"NEAR","name":"Nearest"}},
+				{"id":"https://api.weather.gov/stations/NEXT","geometry":{"coordinates":[-97.2,39.8]},"properties":{"stationIdentifier":"NEXT","name":"Next Nearest"}}
+			]}`
+		case "/stations/NEAR/observations/latest":
+			// A broken sensor reports nulls
+			body = `{"properties":{"timestamp":"` + observed + `","temperature":{"unitCode":"wmoUnit:degC","value":null}}}`
+		case "/stations/NEXT/observations/latest":
+			body = `{"properties":{"timestamp":"` + observed + `","textDescription":"Mostly Cloudy",
+				"temperature":{"unitCode":"wmoUnit:degC","value":21.7},
+				"dewpoint":{"unitCode":"wmoUnit:degC","value":12.1},
+				"relativeHumidity":{"unitCode":"wmoUnit:percent","value":54.3},
+				"windDirection":{"unitCode":"wmoUnit:degree_(angle)","value":200},
+				"windSpeed":{"unitCode":"wmoUnit:km_h-1","value":18.4},
+				"windGust":{"unitCode":"wmoUnit:km_h-1","value":null},
+				"barometricPressure":{"unitCode":"wmoUnit:Pa","value":101560},
+				"visibility":{"unitCode":"wmoUnit:m","value":16090}}}`
+		default:
+			return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(`{}`)), Header: make(http.Header)}, nil
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	router := GetRouter(services.NewClient(services.WithTransport(transport)))
+
+	req := httptest.NewRequest("GET", "/v1/conditions/39.7456/-97.0892?units=si", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var got models.Conditions
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.StationID != "NEXT" || got.StationName != "Next Nearest" || got.DistanceKm <= 0 || got.Description != "Mostly Cloudy" || got.ObservedAt.IsZero() {
+		t.Fatalf("unexpected station: %+v", got)
+	}
+	if *got.Temperature.Value != 21.7 || got.Temperature.Unit != "degC" || *got.BarometricPressure.Value != 101560 || got.WindGust.Value != nil || got.Units != models.SI {
+		t.Fatalf("unexpected measurements: %s", rr.Body.String())
+	}
+
+	// US units are the default
+	req = httptest.NewRequest("GET", "/v1/conditions/39.7456/-97.0892", nil)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	got = models.Conditions{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if *got.Temperature.Value != 71.06 || got.Temperature.Unit != "degF" || *got.WindSpeed.Value != 11.43 || got.WindSpeed.Unit != "mi_h-1" ||
+		*got.BarometricPressure.Value != 29.99 || got.BarometricPressure.Unit != "inHg" || *got.Visibility.Value != 10 || got.Visibility.Unit != "mi" ||
+		*got.RelativeHumidity.Value != 54.3 || got.RelativeHumidity.Unit != "percent" || got.Units != models.US {
+		t.Fatalf("unexpected US measurements: %s", rr.Body.String())
+	}
+}
+
+func TestGetConditions_Errors(t *testing.T) {
+	t.Parallel()
+
+	unavailable := &services.DataUnavailableError{URL: "u", Err: models.ErrNoObservation}
+
+	for path, want := range map[string]int{
+		"/v1/conditions/1/200": http.StatusBadRequest,
+		"/v1/conditions/1/2":   http.StatusServiceUnavailable,
+	} {
+		req := httptest.NewRequest("GET", path, nil)
+		rr := httptest.NewRecorder()
+		GetRouter(stubClient{err: unavailable}).ServeHTTP(rr, req)
+
+		if rr.Code != want {
+			t.Fatalf("%s: got %d want %d", path, rr.Code, want)
+		}
+	}
+}
+
+func TestUnitsParam(t *testing.T) {
+	t.Parallel()
+
+	paths := []string{
+		"/v1/forecasts/1/2",
+		"/v1/forecasts/1/2/periods",
+		"/v1/forecasts/1/2/hourly",
+		"/v1/forecasts/1/2/daily",
+		"/v1/conditions/1/2",
+	}
+
+	for _, path := range paths {
+		for query, want := range map[string]models.UnitSystem{"": "", "?units=us": models.US, "?units=si": models.SI, "?units=metric": models.SI} {
+			var units models.UnitSystem = "unset"
+			client := stubClient{
+				forecast: &models.Forecast{},
+				periods:  &models.ForecastPeriods{},
+				hourly:   &models.HourlyForecast{},
+				daily:    &models.DailyForecast{},
+				conds:    &models.Conditions{},
+				units:    &units,
+			}
+
+			req := httptest.NewRequest("GET", path+query, nil)
+			rr := httptest.NewRecorder()
+			GetRouter(client).ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK || units != want {
+				t.Fatalf("%s%s: got %d and units %q want %q", path, query, rr.Code, units, want)
+			}
+		}
+
+		req := httptest.NewRequest("GET", path+"?units=kelvin", nil)
+		rr := httptest.NewRecorder()
+		GetRouter(stubClient{}).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "kelvin") {
+			t.Fatalf("%s: got %d want %d: %s", path, rr.Code, http.StatusBadRequest, rr.Body.String())
+		}
+	}
+}
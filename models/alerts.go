@@ -0,0 +1,102 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// AlertSeverities are the severities NWS gives alerts, from most to least
+// severe.
+var AlertSeverities = []string{"Extreme", "Severe", "Moderate", "Minor", "Unknown"}
+
+// AlertQuery filters alerts. An alert must have one of Severities and be
+// one of Events, compared case-insensitively; an empty list matches all.
+type AlertQuery struct {
+	Severities []string
+	Events     []string
+}
+
+// Alerts are the weather alerts in effect for a location.
+type Alerts struct {
+	Alerts []Alert `json:"alerts"`
+	Freshness
+}
+
+// Alert is an NWS watch, warning or advisory.
+type Alert struct {
+	ID          string    `json:"id"`
+	Event       string    `json:"event" example:"Tornado Warning"`
+	Severity    string    `json:"severity" example:"Extreme" enums:"Extreme,Severe,Moderate,Minor,Unknown"`
+	Certainty   string    `json:"certainty" example:"Observed"`
+	Urgency     string    `json:"urgency" example:"Immediate"`
+	Headline    string    `json:"headline"`
+	Description string    `json:"description"`
+	Instruction string    `json:"instruction,omitempty"`
+	Effective   time.Time `json:"effective"`
+	Expires     time.Time `json:"expires"`
+	// AreaDescription names the affected area, e.g. "Cook, IL"
+	AreaDescription string `json:"area_description"`
+	// AffectedZones are the NWS zone URLs the alert applies to
+	AffectedZones []string `json:"affected_zones"`
+}
+
+// NewAlertsFromUpstream maps the alerts in upstream that match query and
+// have not expired at now, which may be the case for alerts served from the
+// cache.
+func NewAlertsFromUpstream(upstream *AlertsResponse, query AlertQuery, now time.Time) *Alerts {
+	alerts := []Alert{}
+
+	for _, feature := range upstream.Features {
+		p := feature.Properties
+
+		if !p.Expires.IsZero() && !p.Expires.After(now) {
+			continue
+		}
+
+		if !matches(query.Severities, p.Severity) || !matches(query.Events, p.Event) {
+			continue
+		}
+
+		alerts = append(alerts, Alert{
+			ID:              p.ID,
+			Event:           p.Event,
+			Severity:        p.Severity,
+			Certainty:       p.Certainty,
+			Urgency:         p.Urgency,
+			Headline:        p.Headline,
+			Description:     p.Description,
+			Instruction:     p.Instruction,
+			Effective:       p.Effective,
+			Expires:         p.Expires,
+			AreaDescription: p.AreaDesc,
+			AffectedZones:   p.AffectedZones,
+		})
+	}
+
+	return &Alerts{Alerts: alerts}
+}
+
+func matches(allowed []string, value string) bool {
+	return len(allowed) == 0 || slices.ContainsFunc(allowed, func(a string) bool {
+		return strings.EqualFold(a, value)
+	})
+}
+
+// AlertSummary is the gist of an alert, embedded in forecasts.
+type AlertSummary struct {
+	Event    string    `json:"event" example:"Tornado Warning"`
+	Severity string    `json:"severity" example:"Extreme"`
+	Expires  time.Time `json:"expires,omitzero"`
+}
+
+// Summaries returns the gist of each alert.
+func (a *Alerts) Summaries() []AlertSummary {
+	summaries := make([]AlertSummary, 0, len(a.Alerts))
+
+	for _, alert := range a.Alerts {
+		summaries = append(summaries, AlertSummary{Event: alert.Event, Severity: alert.Severity, Expires: alert.Expires})
+	}
+
+	return summaries
+}
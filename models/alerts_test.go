@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewAlertsFromUpstream(t *testing.T) {
+	now := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+
+	upstream := &AlertsResponse{Features: []AlertFeature{
+		{Properties: AlertProperties{ID: "tornado", Event: "Tornado Warning", Severity: "Extreme", AreaDesc: "Cook, IL", AffectedZones: []string{"https://api.weather.gov/zones/county/ILC031"}, Expires: now.Add(time.Hour)}},
+		{Properties: AlertProperties{ID: "heat", Event: "Heat Advisory", Severity: "Moderate", Expires: now.Add(time.Hour)}},
+		{Properties: AlertProperties{ID: "expired", Event: "Tornado Warning", Severity: "Extreme", Expires: now}},
+		{Properties: AlertProperties{ID: "open-ended", Event: "Flood Watch", Severity: "Severe"}},
+	}}
+
+	tests := []struct {
+		name    string
+		query   AlertQuery
+		wantIDs []string
+	}{
+		{"everything in effect", AlertQuery{}, []string{"tornado", "heat", "open-ended"}},
+		{"by severity", AlertQuery{Severities: []string{"extreme", "Severe"}}, []string{"tornado", "open-ended"}},
+		{"by event", AlertQuery{Events: []string{"heat advisory"}}, []string{"heat"}},
+		{"by severity and event", AlertQuery{Severities: []string{"Extreme"}, Events: []string{"Heat Advisory"}}, []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewAlertsFromUpstream(upstream, tc.query, now)
+
+			ids := []string{}
+			for _, a := range got.Alerts {
+				ids = append(ids, a.ID)
+			}
+			if len(ids) != len(tc.wantIDs) {
+				t.Fatalf("got %v want %v", ids, tc.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tc.wantIDs[i] {
+					t.Fatalf("got %v want %v", ids, tc.wantIDs)
+				}
+			}
+		})
+	}
+
+	tornado := NewAlertsFromUpstream(upstream, AlertQuery{}, now).Alerts[0]
+	if tornado.AreaDescription != "Cook, IL" || len(tornado.AffectedZones) != 1 || !tornado.Expires.Equal(now.Add(time.Hour)) {
+		t.Fatalf("unexpected alert: %+v", tornado)
+	}
+}
+
+func TestAlerts_Summaries(t *testing.T) {
+	expires := time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC)
+	alerts := &Alerts{Alerts: []Alert{{Event: "Flood Watch", Severity: "Severe", Headline: "Flood Watch issued", Expires: expires}}}
+
+	got := alerts.Summaries()
+	if len(got) != 1 || got[0] != (AlertSummary{Event: "Flood Watch", Severity: "Severe", Expires: expires}) {
+		t.Fatalf("unexpected summaries: %+v", got)
+	}
+
+	if got := (&Alerts{}).Summaries(); got == nil || len(got) != 0 {
+		t.Fatalf("expected an empty, non-nil list, got %#v", got)
+	}
+}
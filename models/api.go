@@ -1,5 +1,28 @@
 package models
 
-type APIError struct {
-	Message string `json:"error"`
+import "net/http"
+
+// Problem is an RFC 7807 problem document, served as
+// application/problem+json for every error response.
+type Problem struct {
+	Type     string `json:"type" example:"about:blank"`
+	Title    string `json:"title" example:"Bad Gateway"`
+	Status   int    `json:"status" example:"502"`
+	Detail   string `json:"detail,omitempty" example:"Unexpected Problem"`
+	Instance string `json:"instance,omitempty" example:"/v1/forecasts/39.7456/-97.0892"`
+	// RequestID identifies the request in our logs
+	RequestID string `json:"request_id,omitempty"`
+	// CorrelationID identifies the failed request in NWS's logs
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// NewProblem returns a problem of the generic about:blank type, titled
+// after status.
+func NewProblem(status int, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
 }
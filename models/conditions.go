@@ -0,0 +1,152 @@
+package models
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrNoObservation is returned when none of the stations near a location
+// has a recent observation with a temperature.
+var ErrNoObservation = errors.New("no recent observation from nearby stations")
+
+// Conditions are the weather conditions last observed at the station
+// nearest to a location.
+type Conditions struct {
+	StationID   string `json:"station_id" example:"KTOP"`
+	StationName string `json:"station_name" example:"Topeka, Billard Municipal Airport"`
+	// DistanceKm is how far the station is from the requested location
+	DistanceKm         float64     `json:"distance_km" example:"12.3"`
+	ObservedAt         time.Time   `json:"observed_at"`
+	Description        string      `json:"description" example:"Mostly Cloudy"`
+	Temperature        Measurement `json:"temperature"`
+	Dewpoint           Measurement `json:"dewpoint"`
+	RelativeHumidity   Measurement `json:"relative_humidity"`
+	WindDirection      Measurement `json:"wind_direction"`
+	WindSpeed          Measurement `json:"wind_speed"`
+	WindGust           Measurement `json:"wind_gust"`
+	BarometricPressure Measurement `json:"barometric_pressure"`
+	Visibility         Measurement `json:"visibility"`
+	Units              UnitSystem  `json:"units" example:"si"`
+	Freshness
+}
+
+// Measurement is an observed value and its unit. Value is null when the
+// station did not report it.
+type Measurement struct {
+	Value *float64 `json:"value" example:"21.7"`
+	Unit  string   `json:"unit" example:"degC"`
+}
+
+// convertTo converts m to the unit system uses for its quantity, rounded to
+// 2 decimal places. It is left as it is when its unit is unknown.
+func (m Measurement) convertTo(system UnitSystem) Measurement {
+	converted, err := QuantitativeValue{UnitCode: m.Unit, Value: m.Value}.Convert(system.unitFor(Unit(m.Unit)))
+
+	if err != nil {
+		return m
+	}
+
+	if converted.Value != nil {
+		rounded := math.Round(*converted.Value*100) / 100
+		converted.Value = &rounded
+	}
+
+	return Measurement{Value: converted.Value, Unit: strings.TrimPrefix(converted.UnitCode, "wmoUnit:")}
+}
+
+// Station is an observation station and its distance from a location.
+type Station struct {
+	URL        string
+	ID         string
+	Name       string
+	DistanceKm float64
+}
+
+// NearestStations returns up to limit of the stations in upstream, nearest
+// to coordinate first. Stations without a location are left out.
+func NearestStations(upstream *StationsResponse, coordinate Coordinate, limit int) []Station {
+	var stations []Station
+
+	for _, f := range upstream.Features {
+		if len(f.Geometry.Coordinates) < 2 || f.ID == "" {
+			continue
+		}
+
+		location := Coordinate{Latitude: f.Geometry.Coordinates[1], Longitude: f.Geometry.Coordinates[0]}
+
+		stations = append(stations, Station{
+			URL:        f.ID,
+			ID:         f.Properties.StationIdentifier,
+			Name:       f.Properties.Name,
+			DistanceKm: coordinate.DistanceKm(location),
+		})
+	}
+
+	slices.SortStableFunc(stations, func(a, b Station) int {
+		return cmp.Compare(a.DistanceKm, b.DistanceKm)
+	})
+
+	return stations[:min(limit, len(stations))]
+}
+
+// Usable reports whether the observation was taken within maxAge of now and
+// has a temperature, which stations with broken sensors report as null.
+func (o *ObservationResponse) Usable(now time.Time, maxAge time.Duration) bool {
+	t := o.Properties.Temperature
+
+	return t.Value != nil && !t.Rejected() && now.Sub(o.Properties.Timestamp) <= maxAge
+}
+
+// NewConditionsFromUpstream maps an observation from station.
+func NewConditionsFromUpstream(station Station, upstream *ObservationResponse) *Conditions {
+	p := upstream.Properties
+
+	return &Conditions{
+		StationID:          station.ID,
+		StationName:        station.Name,
+		DistanceKm:         math.Round(station.DistanceKm*10) / 10,
+		ObservedAt:         p.Timestamp,
+		Description:        p.TextDescription,
+		Temperature:        measurement(p.Temperature),
+		Dewpoint:           measurement(p.Dewpoint),
+		RelativeHumidity:   measurement(p.RelativeHumidity),
+		WindDirection:      measurement(p.WindDirection),
+		WindSpeed:          measurement(p.WindSpeed),
+		WindGust:           measurement(p.WindGust),
+		BarometricPressure: measurement(p.BarometricPressure),
+		Visibility:         measurement(p.Visibility),
+	}
+}
+
+// ConvertTo converts every measurement to system. Directions and
+// percentages are the same in every system.
+func (c *Conditions) ConvertTo(system UnitSystem) {
+	for _, m := range []*Measurement{
+		&c.Temperature, &c.Dewpoint, &c.RelativeHumidity, &c.WindDirection,
+		&c.WindSpeed, &c.WindGust, &c.BarometricPressure, &c.Visibility,
+	} {
+		*m = m.convertTo(system)
+	}
+
+	c.Units = system
+}
+
+// measurement maps an observed value, leaving out values that failed
+// quality control.
+func measurement(v QuantitativeValue) Measurement {
+	m := Measurement{Value: v.Value, Unit: strings.TrimPrefix(v.UnitCode, "wmoUnit:")}
+
+	if unit, err := v.Unit(); err == nil {
+		m.Unit = string(unit)
+	}
+
+	if v.Rejected() {
+		m.Value = nil
+	}
+
+	return m
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCoordinate_DistanceKm(t *testing.T) {
+	chicago := Coordinate{Latitude: 41.8781, Longitude: -87.6298}
+	newYork := Coordinate{Latitude: 40.7128, Longitude: -74.0060}
+
+	if got := chicago.DistanceKm(newYork); math.Abs(got-1145) > 5 {
+		t.Fatalf("Chicago to New York: got %.0f km want about 1145", got)
+	}
+	if got := chicago.DistanceKm(chicago); got != 0 {
+		t.Fatalf("distance to itself: got %f", got)
+	}
+}
+
+func TestNearestStations(t *testing.T) {
+	station := func(id string, lon, lat float64) StationFeature {
+		var f StationFeature
+		f.ID = "https://api.weather.gov/stations/" + id
+		f.Geometry.Coordinates = []float64{lon, lat}
+		f.Properties.StationIdentifier = id
+		return f
+	}
+
+	upstream := &StationsResponse{Features: []StationFeature{
+		station("FAR", -90, 40),
+		station("NEAR", -97.1, 39.7),
+		{ID: "https://api.weather.gov/stations/NOWHERE"},
+		station("MID", -98, 40),
+	}}
+
+	got := NearestStations(upstream, Coordinate{Latitude: 39.7456, Longitude: -97.0892}, 2)
+	if len(got) != 2 || got[0].ID != "NEAR" || got[1].ID != "MID" || got[0].DistanceKm >= got[1].DistanceKm {
+		t.Fatalf("unexpected stations: %+v", got)
+	}
+	if got[0].URL != "https://api.weather.gov/stations/NEAR" {
+		t.Fatalf("unexpected URL: %q", got[0].URL)
+	}
+
+	if got := NearestStations(&StationsResponse{}, Coordinate{}, 3); len(got) != 0 {
+		t.Fatalf("expected no stations, got %+v", got)
+	}
+}
+
+func TestObservationResponse_Usable(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	temp := 20.0
+
+	tests := []struct {
+		name string
+		obs  ObservationProperties
+		want bool
+	}{
+		{"recent with temperature", ObservationProperties{Timestamp: now.Add(-time.Hour), Temperature: QuantitativeValue{Value: &temp}}, true},
+		{"null temperature", ObservationProperties{Timestamp: now.Add(-time.Hour)}, false},
+		{"too old", ObservationProperties{Timestamp: now.Add(-3 * time.Hour), Temperature: QuantitativeValue{Value: &temp}}, false},
+		{"failed quality control", ObservationProperties{Timestamp: now.Add(-time.Hour), Temperature: QuantitativeValue{Value: &temp, QualityControl: "X"}}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obs := &ObservationResponse{Properties: tc.obs}
+			if got := obs.Usable(now, 2*time.Hour); got != tc.want {
+				t.Fatalf("got %v want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewConditionsFromUpstream(t *testing.T) {
+	temp, gust := 21.7, 90.0
+
+	var obs ObservationResponse
+	obs.Properties.Temperature = QuantitativeValue{UnitCode: "wmoUnit:degC", Value: &temp, QualityControl: "V"}
+	obs.Properties.WindGust = QuantitativeValue{UnitCode: "wmoUnit:km_h-1", Value: &gust, QualityControl: "X"}
+	obs.Properties.Visibility = QuantitativeValue{UnitCode: "unit:m"}
+
+	got := NewConditionsFromUpstream(Station{ID: "KTOP", DistanceKm: 12.34}, &obs)
+
+	if got.DistanceKm != 12.3 || got.Temperature.Unit != "degC" || got.Temperature.Value == nil || *got.Temperature.Value != temp {
+		t.Fatalf("unexpected conditions: %+v", got)
+	}
+	if got.WindGust.Value != nil || got.WindGust.Unit != "km_h-1" {
+		t.Fatalf("value that failed quality control: got %+v", got.WindGust)
+	}
+	if got.Visibility.Unit != "m" {
+		t.Fatalf("legacy unit code: got %q", got.Visibility.Unit)
+	}
+}
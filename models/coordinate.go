@@ -0,0 +1,104 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+// coordinatePrecision is the number of decimal places NWS accepts; it
+// redirects requests for more precise points to the rounded ones.
+const coordinatePrecision = 4
+
+// decimalPattern matches a plain decimal number. It keeps out whatever else
+// strconv.ParseFloat accepts, such as NaN, Inf, exponents and hex floats, as
+// well as whitespace and anything that would change the NWS path.
+var decimalPattern = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?$`)
+
+// Coordinate is a validated point on the globe, rounded to the precision
+// NWS accepts.
+type Coordinate struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// CoordinateError is returned when a latitude or longitude cannot be
+// parsed or is out of range.
+type CoordinateError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *CoordinateError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+// ParseCoordinate parses latitude and longitude given in decimal degrees.
+// Latitude must be within [-90, 90] and longitude within [-180, 180].
+func ParseCoordinate(latitude, longitude string) (Coordinate, error) {
+	lat, err := parseDegrees("latitude", latitude, 90)
+
+	if err != nil {
+		return Coordinate{}, err
+	}
+
+	lon, err := parseDegrees("longitude", longitude, 180)
+
+	if err != nil {
+		return Coordinate{}, err
+	}
+
+	return Coordinate{Latitude: lat, Longitude: lon}, nil
+}
+
+func parseDegrees(field, value string, limit float64) (float64, error) {
+	if !decimalPattern.MatchString(value) {
+		return 0, &CoordinateError{Field: field, Value: value, Reason: "must be a decimal number of degrees"}
+	}
+
+	degrees, err := strconv.ParseFloat(value, 64)
+
+	if err != nil || math.Abs(degrees) > limit {
+		return 0, &CoordinateError{Field: field, Value: value, Reason: fmt.Sprintf("must be between -%g and %g", limit, limit)}
+	}
+
+	return round(degrees), nil
+}
+
+func round(degrees float64) float64 {
+	scale := math.Pow10(coordinatePrecision)
+	rounded := math.Round(degrees*scale) / scale
+
+	// Avoid sending -0 for small negative values
+	if rounded == 0 {
+		return 0
+	}
+
+	return rounded
+}
+
+// String formats c as NWS expects it in /points paths, e.g. "39.7456,-97.0892".
+func (c Coordinate) String() string {
+	return formatDegrees(c.Latitude) + "," + formatDegrees(c.Longitude)
+}
+
+func formatDegrees(degrees float64) string {
+	return strconv.FormatFloat(round(degrees), 'f', -1, 64)
+}
+
+// earthRadiusKm is the mean radius of the Earth.
+const earthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle distance between c and other.
+func (c Coordinate) DistanceKm(other Coordinate) float64 {
+	lat1 := c.Latitude * math.Pi / 180
+	lat2 := other.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (other.Longitude - c.Longitude) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
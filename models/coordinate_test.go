@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCoordinate(t *testing.T) {
+	tests := []struct {
+		name      string
+		latitude  string
+		longitude string
+		want      string
+	}{
+		{"integers", "1", "2", "1,2"},
+		{"four decimals", "39.7456", "-97.0892", "39.7456,-97.0892"},
+		{"rounded to four decimals", "39.74564", "-97.08925", "39.7456,-97.0893"},
+		{"trailing zeros trimmed", "39.7000", "-97.0800", "39.7,-97.08"},
+		{"explicit plus sign", "+39.7456", "+97.0892", "39.7456,97.0892"},
+		{"negative zero", "-0.00001", "-0", "0,0"},
+		{"bounds", "-90", "180", "-90,180"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := ParseCoordinate(tc.latitude, tc.longitude)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := c.String(); got != tc.want {
+				t.Fatalf("got %q want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseCoordinate_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		latitude  string
+		longitude string
+		wantField string
+	}{
+		{"not a number", "abc", "2", "latitude"},
+		{"empty", "", "2", "latitude"},
+		{"latitude too large", "91", "2", "latitude"},
+		{"latitude too small", "-90.0001", "2", "latitude"},
+		{"longitude out of range", "1", "180.5", "longitude"},
+		{"NaN", "NaN", "2", "latitude"},
+		{"infinity", "1", "Inf", "longitude"},
+		{"exponent", "1e1", "2", "latitude"},
+		{"hex float", "0x1p-2", "2", "latitude"},
+		{"whitespace", " 1", "2", "latitude"},
+		{"extra path segments", "1", "2/../alerts", "longitude"},
+		{"second point", "1", "2,3", "longitude"},
+		{"trailing dot", "1.", "2", "latitude"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseCoordinate(tc.latitude, tc.longitude)
+
+			var coordErr *CoordinateError
+			if !errors.As(err, &coordErr) {
+				t.Fatalf("expected *CoordinateError, got %v", err)
+			}
+			if coordErr.Field != tc.wantField {
+				t.Fatalf("field: got %q want %q", coordErr.Field, tc.wantField)
+			}
+		})
+	}
+}
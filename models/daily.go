@@ -0,0 +1,200 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// DailyForecast is the forecast for a location summarised per local
+// calendar day.
+type DailyForecast struct {
+	Days  []DayForecast `json:"days"`
+	Units UnitSystem    `json:"units" example:"us"`
+	Freshness
+}
+
+// DayForecast summarises one local calendar day. High and Low are null
+// when the forecast does not cover the day or night, as happens for today
+// once NWS has moved on to "Tonight".
+type DayForecast struct {
+	Date string `json:"date" example:"2024-03-01"`
+	// High is the highest temperature of the day, in temperature_unit
+	High *int `json:"high" example:"72"`
+	// Low is the lowest temperature of the day, in temperature_unit
+	Low             *int   `json:"low" example:"48"`
+	TemperatureUnit string `json:"temperature_unit" example:"F"`
+	// Condition is the short forecast that covers most of the day
+	Condition string `json:"condition" example:"Partly Sunny"`
+	// ProbabilityOfPrecipitation is the highest of the day, as a percentage
+	ProbabilityOfPrecipitation *int        `json:"probability_of_precipitation" example:"40"`
+	Day                        *DaySummary `json:"day,omitempty"`
+	Night                      *DaySummary `json:"night,omitempty"`
+}
+
+// DaySummary is the NWS forecast for the daytime or night period of a day.
+type DaySummary struct {
+	Name string `json:"name" example:"Saturday"`
+	// Temperature is in the temperature_unit of the day
+	Temperature      int    `json:"temperature" example:"72"`
+	ShortForecast    string `json:"short_forecast" example:"Partly Sunny"`
+	DetailedForecast string `json:"detailed_forecast"`
+}
+
+// dayTotals collects what is known about one local day while folding.
+type dayTotals struct {
+	forecast DayForecast
+	// hours counts the hourly periods with each short forecast
+	hours map[string]int
+}
+
+// NewDailyForecastFromUpstream folds the day and night periods of an NWS
+// forecast, and the hourly forecast when there is one, into one summary per
+// calendar day in loc. A nil loc uses the UTC offset each period's start
+// time was given in.
+//
+// Periods belong to the day they start on. The high is the highest
+// temperature of the daytime period and the hours, and the low the lowest
+// of the night period and the hours. The condition is the short forecast
+// covering the most hours, or without hourly data that of the daytime
+// period, else the night. Hourly periods given in another temperature unit
+// or that are malformed are left out.
+func NewDailyForecastFromUpstream(upstream, hourly *ForecastResponse, loc *time.Location) (*DailyForecast, error) {
+	if upstream == nil || len(upstream.Properties.Periods) == 0 {
+		return nil, ErrNoPeriods
+	}
+
+	days := map[string]*dayTotals{}
+
+	day := func(start time.Time) *dayTotals {
+		date := localDate(start, loc)
+
+		if days[date] == nil {
+			days[date] = &dayTotals{forecast: DayForecast{Date: date}, hours: map[string]int{}}
+		}
+
+		return days[date]
+	}
+
+	unit := upstream.Properties.Periods[0].TemperatureUnit
+
+	for _, p := range upstream.Properties.Periods {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+
+		d := day(p.StartTime)
+		d.forecast.TemperatureUnit = p.TemperatureUnit
+		d.forecast.ProbabilityOfPrecipitation = maxOf(d.forecast.ProbabilityOfPrecipitation, percent(p.ProbabilityOfPrecipitation))
+
+		summary := &DaySummary{Name: p.Name, Temperature: *p.Temperature, ShortForecast: p.ShortForecast, DetailedForecast: p.DetailedForecast}
+
+		if p.IsDaytime {
+			d.forecast.Day = summary
+			d.forecast.High = maxOf(d.forecast.High, p.Temperature)
+		} else {
+			d.forecast.Night = summary
+			d.forecast.Low = minOf(d.forecast.Low, p.Temperature)
+		}
+	}
+
+	if hourly != nil {
+		for _, h := range hourly.Properties.Periods {
+			d, ok := days[localDate(h.StartTime, loc)]
+
+			if !ok || h.validate() != nil || h.TemperatureUnit != unit {
+				continue
+			}
+
+			d.forecast.High = maxOf(d.forecast.High, h.Temperature)
+			d.forecast.Low = minOf(d.forecast.Low, h.Temperature)
+			d.forecast.ProbabilityOfPrecipitation = maxOf(d.forecast.ProbabilityOfPrecipitation, percent(h.ProbabilityOfPrecipitation))
+			d.hours[h.ShortForecast]++
+		}
+	}
+
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	slices.Sort(dates)
+
+	result := &DailyForecast{Days: make([]DayForecast, 0, len(dates))}
+
+	for _, date := range dates {
+		d := days[date]
+		d.forecast.Condition = d.condition()
+		result.Days = append(result.Days, d.forecast)
+	}
+
+	return result, nil
+}
+
+// ConvertTo converts the temperatures of every day to system.
+func (f *DailyForecast) ConvertTo(system UnitSystem) {
+	for i := range f.Days {
+		d := &f.Days[i]
+		temps := []*int{d.High, d.Low}
+
+		if d.Day != nil {
+			temps = append(temps, &d.Day.Temperature)
+		}
+
+		if d.Night != nil {
+			temps = append(temps, &d.Night.Temperature)
+		}
+
+		d.TemperatureUnit = convertTemperatures(d.TemperatureUnit, system, temps...)
+	}
+
+	f.Units = system
+}
+
+func localDate(t time.Time, loc *time.Location) string {
+	if loc != nil {
+		t = t.In(loc)
+	}
+
+	return t.Format(time.DateOnly)
+}
+
+// condition returns the short forecast covering the most hours, breaking
+// ties alphabetically so the result is stable.
+func (d *dayTotals) condition() string {
+	best, most := "", 0
+
+	for forecast, hours := range d.hours {
+		if hours > most || (hours == most && forecast < best) {
+			best, most = forecast, hours
+		}
+	}
+
+	if best != "" {
+		return best
+	}
+
+	if d.forecast.Day != nil {
+		return d.forecast.Day.ShortForecast
+	}
+
+	return d.forecast.Night.ShortForecast
+}
+
+func maxOf(current, v *int) *int {
+	if v == nil || (current != nil && *current >= *v) {
+		return current
+	}
+
+	value := *v
+
+	return &value
+}
+
+func minOf(current, v *int) *int {
+	if v == nil || (current != nil && *current <= *v) {
+		return current
+	}
+
+	value := *v
+
+	return &value
+}
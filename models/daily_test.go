@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewDailyForecastFromUpstream(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var forecast, hourly ForecastResponse
+	if err := json.Unmarshal([]byte(`{"properties":{"periods":[
+		{"name":"Tonight","startTime":"2024-03-01T18:00:00-06:00","endTime":"2024-03-02T06:00:00-06:00","isDaytime":false,"temperature":38,"temperatureUnit":"F","shortForecast":"Clear"},
+		{"name":"Saturday","startTime":"2024-03-02T06:00:00-06:00","endTime":"2024-03-02T18:00:00-06:00","isDaytime":true,"temperature":66,"temperatureUnit":"F","shortForecast":"Mostly Sunny","probabilityOfPrecipitation":{"value":10}},
+		{"name":"Saturday Night","startTime":"2024-03-02T18:00:00-06:00","endTime":"2024-03-03T06:00:00-06:00","isDaytime":false,"temperature":45,"temperatureUnit":"F","shortForecast":"Rain Likely","probabilityOfPrecipitation":{"value":70}}
+	]}}`), &forecast); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"properties":{"periods":[
+		{"startTime":"2024-03-02T05:00:00-06:00","endTime":"2024-03-02T06:00:00-06:00","temperature":35,"temperatureUnit":"F","shortForecast":"Clear"},
+		{"startTime":"2024-03-02T13:00:00-06:00","endTime":"2024-03-02T14:00:00-06:00","temperature":68,"temperatureUnit":"F","shortForecast":"Sunny","probabilityOfPrecipitation":{"value":5}},
+		{"startTime":"2024-03-02T14:00:00-06:00","endTime":"2024-03-02T15:00:00-06:00","temperature":67,"temperatureUnit":"F","shortForecast":"Sunny"},
+		{"startTime":"2024-03-02T15:00:00-06:00","endTime":"2024-03-02T16:00:00-06:00","temperature":99,"temperatureUnit":"C","shortForecast":"Sunny"},
+		{"startTime":"2024-03-02T16:00:00-06:00","endTime":"2024-03-02T17:00:00-06:00","temperatureUnit":"F","shortForecast":"Sunny"},
+		{"startTime":"2024-03-05T16:00:00-06:00","endTime":"2024-03-05T17:00:00-06:00","temperature":20,"temperatureUnit":"F","shortForecast":"Snow"}
+	]}}`), &hourly); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewDailyForecastFromUpstream(&forecast, &hourly, chicago)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Days) != 2 || got.Days[0].Date != "2024-03-01" || got.Days[1].Date != "2024-03-02" {
+		t.Fatalf("unexpected days: %+v", got.Days)
+	}
+
+	friday := got.Days[0]
+	if friday.High != nil || *friday.Low != 38 || friday.Day != nil || friday.Night.Name != "Tonight" || friday.Condition != "Clear" || friday.ProbabilityOfPrecipitation != nil {
+		t.Fatalf("unexpected Friday: %+v", friday)
+	}
+
+	saturday := got.Days[1]
+	if *saturday.High != 68 || *saturday.Low != 35 || saturday.TemperatureUnit != "F" {
+		t.Fatalf("unexpected Saturday temperatures: high %d low %d", *saturday.High, *saturday.Low)
+	}
+	if saturday.Condition != "Sunny" || *saturday.ProbabilityOfPrecipitation != 70 {
+		t.Fatalf("unexpected Saturday: %+v", saturday)
+	}
+	if saturday.Day.Temperature != 66 || saturday.Night.ShortForecast != "Rain Likely" {
+		t.Fatalf("unexpected Saturday summaries: %+v %+v", saturday.Day, saturday.Night)
+	}
+
+	withoutHourly, err := NewDailyForecastFromUpstream(&forecast, nil, chicago)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saturday := withoutHourly.Days[1]; *saturday.High != 66 || *saturday.Low != 45 || saturday.Condition != "Mostly Sunny" {
+		t.Fatalf("unexpected Saturday without hourly data: %+v", saturday)
+	}
+}
+
+func TestNewDailyForecastFromUpstream_Invalid(t *testing.T) {
+	if _, err := NewDailyForecastFromUpstream(&ForecastResponse{}, nil, nil); !errors.Is(err, ErrNoPeriods) {
+		t.Fatalf("expected ErrNoPeriods, got %v", err)
+	}
+
+	malformed := &ForecastResponse{Properties: ForecastProperties{Periods: []ForecastPeriod{{Name: "Today"}}}}
+	if _, err := NewDailyForecastFromUpstream(malformed, nil, nil); !errors.Is(err, ErrMalformedPeriod) {
+		t.Fatalf("expected ErrMalformedPeriod, got %v", err)
+	}
+}
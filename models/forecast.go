@@ -1,5 +1,19 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNoPeriods is returned when an NWS forecast has no periods.
+	ErrNoPeriods = errors.New("forecast has no periods")
+	// ErrMalformedPeriod is returned when a forecast period lacks a
+	// required field.
+	ErrMalformedPeriod = errors.New("malformed forecast period")
+)
+
 type Characterization string
 
 const (
@@ -13,6 +27,27 @@ type Forecast struct {
 	ForecastDaily    string           `json:"forecast_daily"`
 	Characterization Characterization `json:"temperature_characterization"`
 	Temperature      int              `json:"temperature"`
+	TemperatureUnit  string           `json:"temperature_unit" example:"F"`
+	Units            UnitSystem       `json:"units" example:"us"`
+	// Period is the NWS forecast period the forecast was taken from
+	Period Period `json:"period"`
+	// Alerts in effect, only included when asked for. AlertsError says
+	// why they are missing when they could not be fetched, and AlertsStale
+	// that they were served from the cache after they expired, so newer
+	// alerts may be missing.
+	Alerts           []AlertSummary `json:"alerts,omitzero"`
+	AlertsError      string         `json:"alerts_error,omitempty"`
+	AlertsStale      bool           `json:"alerts_stale,omitempty"`
+	AlertsAgeSeconds int            `json:"alerts_age_seconds,omitempty"`
+	Freshness
+}
+
+// Period identifies an NWS forecast period.
+type Period struct {
+	Name      string    `json:"name" example:"Today"`
+	StartTime time.Time `json:"start_time,omitzero"`
+	EndTime   time.Time `json:"end_time,omitzero"`
+	IsDaytime bool      `json:"is_daytime"`
 }
 
 func MapCharacterizationFromTemp(temp int) Characterization {
@@ -31,10 +66,82 @@ func MapCharacterizationFromTemp(temp int) Characterization {
 	return Unknown
 }
 
-func NewForecastFromUpstream(upstream *ForecastResponse) *Forecast {
+// NewForecastFromUpstream maps the period of an NWS forecast that covers
+// today at now, as chosen by TodayPeriod. It fails with ErrNoPeriods when
+// NWS returned none, as it does during grid maintenance, and with
+// ErrMalformedPeriod when a required field is missing.
+func NewForecastFromUpstream(upstream *ForecastResponse, now time.Time, loc *time.Location) (*Forecast, error) {
+	if upstream == nil || len(upstream.Properties.Periods) == 0 {
+		return nil, ErrNoPeriods
+	}
+
+	period := TodayPeriod(upstream.Properties.Periods, now, loc)
+
+	if err := period.validate(); err != nil {
+		return nil, err
+	}
+
 	return &Forecast{
-		ForecastDaily:    upstream.Properties.Periods[0].ShortForecast,
-		Characterization: MapCharacterizationFromTemp(upstream.Properties.Periods[0].Temperature),
-		Temperature:      upstream.Properties.Periods[0].Temperature,
+		ForecastDaily:    period.ShortForecast,
+		Characterization: characterize(*period.Temperature, period.TemperatureUnit),
+		Temperature:      *period.Temperature,
+		TemperatureUnit:  period.TemperatureUnit,
+		Period:           period.summary(),
+	}, nil
+}
+
+// ConvertTo converts the temperature to system.
+func (f *Forecast) ConvertTo(system UnitSystem) {
+	f.TemperatureUnit = convertTemperatures(f.TemperatureUnit, system, &f.Temperature)
+	f.Units = system
+}
+
+// TodayPeriod chooses the period that best describes today at now, with
+// calendar dates taken in loc, the location's time zone. It is the first
+// daytime period that starts on today's date and has not ended. Failing
+// that, such as in the evening once NWS has moved on to "Tonight", it is
+// the period in effect at now, and otherwise the first period. A nil loc
+// uses the UTC offset each period's start time was given in. periods must
+// not be empty.
+func TodayPeriod(periods []ForecastPeriod, now time.Time, loc *time.Location) ForecastPeriod {
+	for _, p := range periods {
+		if p.IsDaytime && !p.StartTime.IsZero() && p.EndTime.After(now) && sameDate(p.StartTime, now, loc) {
+			return p
+		}
 	}
+
+	for _, p := range periods {
+		if !p.StartTime.After(now) && p.EndTime.After(now) {
+			return p
+		}
+	}
+
+	return periods[0]
+}
+
+func sameDate(a, b time.Time, loc *time.Location) bool {
+	if loc == nil {
+		loc = a.Location()
+	}
+
+	ay, am, ad := a.In(loc).Date()
+	by, bm, bd := b.In(loc).Date()
+
+	return ay == by && am == bm && ad == bd
+}
+
+func (p ForecastPeriod) summary() Period {
+	return Period{Name: p.Name, StartTime: p.StartTime, EndTime: p.EndTime, IsDaytime: p.IsDaytime}
+}
+
+func (p ForecastPeriod) validate() error {
+	if p.Temperature == nil {
+		return fmt.Errorf("%w: %q has no temperature", ErrMalformedPeriod, p.Name)
+	}
+
+	if p.ShortForecast == "" {
+		return fmt.Errorf("%w: %q has no short forecast", ErrMalformedPeriod, p.Name)
+	}
+
+	return nil
 }
@@ -1,6 +1,10 @@
 package models
 
-import "testing"
+import (
+	"errors"
+	"testing"
+	"time"
+)
 
 func TestMapCharacterizationFromTemp(t *testing.T) {
 	tests := []struct {
@@ -45,29 +49,19 @@ func TestNewForecastFromUpstream(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			fr := &ForecastResponse{
-				Properties: struct {
-					Periods []struct {
-						Name          string `json:"name"`
-						Temperature   int    `json:"temperature"`
-						ShortForecast string `json:"shortForecast"`
-					} `json:"periods"`
-				}{
-					Periods: []struct {
-						Name          string `json:"name"`
-						Temperature   int    `json:"temperature"`
-						ShortForecast string `json:"shortForecast"`
-					}{
+				Properties: ForecastProperties{
+					Periods: []ForecastPeriod{
 						{
 							ShortForecast: tc.shortForecast,
-							Temperature:   tc.temp,
+							Temperature:   &tc.temp,
 						},
 					},
 				},
 			}
 
-			got := NewForecastFromUpstream(fr)
-			if got == nil {
-				t.Fatal("NewForecastFromUpstream returned nil")
+			got, err := NewForecastFromUpstream(fr, time.Now(), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
 			}
 
 			if got.ForecastDaily != tc.wantForecast {
@@ -84,3 +78,113 @@ func TestNewForecastFromUpstream(t *testing.T) {
 		})
 	}
 }
+
+func TestNewForecastFromUpstream_Invalid(t *testing.T) {
+	temp := 70
+
+	tests := []struct {
+		name     string
+		upstream *ForecastResponse
+		wantErr  error
+	}{
+		{"nil response", nil, ErrNoPeriods},
+		{"no periods", &ForecastResponse{}, ErrNoPeriods},
+		{"missing temperature", &ForecastResponse{Properties: ForecastProperties{Periods: []ForecastPeriod{{Name: "Today", ShortForecast: "Sunny"}}}}, ErrMalformedPeriod},
+		{"missing short forecast", &ForecastResponse{Properties: ForecastProperties{Periods: []ForecastPeriod{{Name: "Today", Temperature: &temp}}}}, ErrMalformedPeriod},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NewForecastFromUpstream(tc.upstream, time.Now(), nil)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error: got %v want %v", err, tc.wantErr)
+			}
+			if got != nil {
+				t.Fatalf("expected no forecast, got %+v", got)
+			}
+		})
+	}
+}
+
+// chicagoPeriods is the start of an NWS forecast issued in the early
+// afternoon of 2024-03-01 for a point in America/Chicago.
+func chicagoPeriods(t *testing.T) []ForecastPeriod {
+	t.Helper()
+
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	temp := func(v int) *int { return &v }
+
+	return []ForecastPeriod{
+		{Number: 1, Name: "This Afternoon", StartTime: at("2024-03-01T13:00:00-06:00"), EndTime: at("2024-03-01T18:00:00-06:00"), IsDaytime: true, Temperature: temp(61), ShortForecast: "Sunny"},
+		{Number: 2, Name: "Tonight", StartTime: at("2024-03-01T18:00:00-06:00"), EndTime: at("2024-03-02T06:00:00-06:00"), IsDaytime: false, Temperature: temp(38), ShortForecast: "Clear"},
+		{Number: 3, Name: "Saturday", StartTime: at("2024-03-02T06:00:00-06:00"), EndTime: at("2024-03-02T18:00:00-06:00"), IsDaytime: true, Temperature: temp(66), ShortForecast: "Mostly Sunny"},
+		{Number: 4, Name: "Saturday Night", StartTime: at("2024-03-02T18:00:00-06:00"), EndTime: at("2024-03-03T06:00:00-06:00"), IsDaytime: false, Temperature: temp(45), ShortForecast: "Partly Cloudy"},
+	}
+}
+
+func TestTodayPeriod(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	periods := chicagoPeriods(t)
+
+	tests := []struct {
+		name     string
+		periods  []ForecastPeriod
+		now      string
+		loc      *time.Location
+		wantName string
+	}{
+		{"afternoon picks today's daytime period", periods, "2024-03-01T14:00:00-06:00", chicago, "This Afternoon"},
+		{"before dawn picks the coming daytime period", periods[1:], "2024-03-02T02:00:00-06:00", chicago, "Saturday"},
+		{"late evening in UTC is still today locally", periods, "2024-03-02T01:30:00Z", chicago, "Tonight"},
+		{"evening falls back to the period in effect", periods[1:], "2024-03-01T20:00:00-06:00", chicago, "Tonight"},
+		{"ended daytime period is skipped", periods, "2024-03-01T19:00:00-06:00", chicago, "Tonight"},
+		{"dates follow the given time zone", periods, "2024-03-01T19:00:00-06:00", time.UTC, "Saturday"},
+		{"nil location uses the period offsets", periods, "2024-03-01T14:00:00-06:00", nil, "This Afternoon"},
+		{"nothing matches falls back to the first period", periods, "2024-03-05T12:00:00-06:00", chicago, "This Afternoon"},
+		{"periods without times fall back to the first period", []ForecastPeriod{{Name: "First"}, {Name: "Second", IsDaytime: true}}, "2024-03-01T14:00:00-06:00", chicago, "First"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, tc.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := TodayPeriod(tc.periods, now, tc.loc); got.Name != tc.wantName {
+				t.Fatalf("got %q want %q", got.Name, tc.wantName)
+			}
+		})
+	}
+}
+
+func TestNewForecastFromUpstream_ChosenPeriod(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	periods := chicagoPeriods(t)
+	now := periods[2].StartTime.Add(-4 * time.Hour)
+
+	got, err := NewForecastFromUpstream(&ForecastResponse{Properties: ForecastProperties{Periods: periods[1:]}}, now, chicago)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Period{Name: "Saturday", StartTime: periods[2].StartTime, EndTime: periods[2].EndTime, IsDaytime: true}
+	if got.Period != want || got.Temperature != 66 || got.ForecastDaily != "Mostly Sunny" {
+		t.Fatalf("unexpected forecast: %+v", got)
+	}
+}
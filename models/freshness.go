@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Freshness says whether a response was served stale, and how long ago it
+// was fetched from NWS.
+type Freshness struct {
+	// Stale is set when the response was served from the cache after it
+	// expired, either while refreshing it or because NWS was unavailable.
+	Stale      bool `json:"stale,omitempty"`
+	AgeSeconds int  `json:"age_seconds,omitempty"`
+}
+
+// MarkStale marks the response as served stale, age after it was fetched.
+func (f *Freshness) MarkStale(age time.Duration) {
+	f.Stale = true
+	f.AgeSeconds = int(age.Seconds())
+}
@@ -0,0 +1,107 @@
+package models
+
+import "time"
+
+// MaxHourlyHours is the most hours NWS forecasts hourly, about 6.5 days.
+const MaxHourlyHours = 156
+
+// HourlyQuery selects the hours of an hourly forecast. Start defaults to
+// now and Hours, when zero, to every hour NWS forecasts after Start.
+type HourlyQuery struct {
+	Start time.Time
+	Hours int
+}
+
+// HourlyForecast is the hour by hour forecast for a location.
+type HourlyForecast struct {
+	Hours []HourForecast `json:"hours"`
+	Units UnitSystem     `json:"units" example:"us"`
+	Freshness
+}
+
+// HourForecast is the forecast for one hour.
+type HourForecast struct {
+	StartTime        time.Time        `json:"start_time"`
+	EndTime          time.Time        `json:"end_time"`
+	IsDaytime        bool             `json:"is_daytime"`
+	Temperature      int              `json:"temperature" example:"72"`
+	TemperatureUnit  string           `json:"temperature_unit" example:"F"`
+	Characterization Characterization `json:"temperature_characterization"`
+	// Dewpoint is in temperature_unit, null when NWS gives none
+	Dewpoint *int `json:"dewpoint" example:"55"`
+	// RelativeHumidity is a percentage, null when NWS gives none
+	RelativeHumidity *int `json:"relative_humidity" example:"60"`
+	// ProbabilityOfPrecipitation is a percentage, null when NWS gives none
+	ProbabilityOfPrecipitation *int   `json:"probability_of_precipitation" example:"20"`
+	WindSpeed                  string `json:"wind_speed" example:"10 mph"`
+	WindDirection              string `json:"wind_direction" example:"SW"`
+	Icon                       string `json:"icon"`
+	ShortForecast              string `json:"short_forecast" example:"Partly Sunny"`
+}
+
+// NewHourlyForecastFromUpstream maps the hours of an NWS hourly forecast
+// selected by query, starting with the hour in effect at query.Start. It
+// fails like NewForecastFromUpstream when there are no periods or a
+// selected one is malformed.
+func NewHourlyForecastFromUpstream(upstream *ForecastResponse, query HourlyQuery) (*HourlyForecast, error) {
+	if upstream == nil || len(upstream.Properties.Periods) == 0 {
+		return nil, ErrNoPeriods
+	}
+
+	hours := []HourForecast{}
+
+	for _, p := range upstream.Properties.Periods {
+		if query.Hours > 0 && len(hours) == query.Hours {
+			break
+		}
+
+		if !p.EndTime.After(query.Start) {
+			continue
+		}
+
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+
+		hours = append(hours, HourForecast{
+			StartTime:                  p.StartTime,
+			EndTime:                    p.EndTime,
+			IsDaytime:                  p.IsDaytime,
+			Temperature:                *p.Temperature,
+			TemperatureUnit:            p.TemperatureUnit,
+			Characterization:           characterize(*p.Temperature, p.TemperatureUnit),
+			Dewpoint:                   degrees(p.Dewpoint, p.TemperatureUnit),
+			RelativeHumidity:           percent(p.RelativeHumidity),
+			ProbabilityOfPrecipitation: percent(p.ProbabilityOfPrecipitation),
+			WindSpeed:                  p.WindSpeed,
+			WindDirection:              p.WindDirection,
+			Icon:                       p.Icon,
+			ShortForecast:              p.ShortForecast,
+		})
+	}
+
+	return &HourlyForecast{Hours: hours}, nil
+}
+
+// ConvertTo converts the temperatures and dewpoints of every hour to
+// system. Wind speeds are left as NWS wrote them.
+func (f *HourlyForecast) ConvertTo(system UnitSystem) {
+	for i := range f.Hours {
+		h := &f.Hours[i]
+		h.TemperatureUnit = convertTemperatures(h.TemperatureUnit, system, &h.Temperature, h.Dewpoint)
+	}
+
+	f.Units = system
+}
+
+// degrees converts a temperature NWS gives in Celsius, as it does dewpoints,
+// to unit. Values in a unit it does not know are left as they are.
+func degrees(v QuantitativeValue, unit string) *int {
+	converted, err := v.Convert(temperatureUnit(unit))
+
+	if err != nil {
+		return v.Round()
+	}
+
+	return converted.Round()
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewHourlyForecastFromUpstream(t *testing.T) {
+	start := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	temp := 10
+	dewpoint := 4.4
+	humidity := 66.6
+
+	var periods []ForecastPeriod
+	for i := range 4 {
+		periods = append(periods, ForecastPeriod{
+			StartTime:       start.Add(time.Duration(i) * time.Hour),
+			EndTime:         start.Add(time.Duration(i+1) * time.Hour),
+			Temperature:     &temp,
+			TemperatureUnit: "C",
+			Dewpoint:        QuantitativeValue{UnitCode: "wmoUnit:degC", Value: &dewpoint},
+			RelativeHumidity: QuantitativeValue{
+				UnitCode: "wmoUnit:percent",
+				Value:    &humidity,
+			},
+			ShortForecast: "Clear",
+		})
+	}
+	upstream := &ForecastResponse{Properties: ForecastProperties{Periods: periods}}
+
+	got, err := NewHourlyForecastFromUpstream(upstream, HourlyQuery{Start: start.Add(90 * time.Minute), Hours: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Hours) != 2 || !got.Hours[0].StartTime.Equal(start.Add(time.Hour)) {
+		t.Fatalf("expected 2 hours from the one in effect at start, got %+v", got.Hours)
+	}
+
+	hour := got.Hours[0]
+	if *hour.Dewpoint != 4 || *hour.RelativeHumidity != 67 || hour.ProbabilityOfPrecipitation != nil || hour.Characterization != Cold {
+		t.Fatalf("unexpected hour: %+v", hour)
+	}
+
+	// A malformed hour before start is skipped rather than failing
+	periods[0].Temperature = nil
+	if _, err := NewHourlyForecastFromUpstream(upstream, HourlyQuery{Start: start.Add(time.Hour)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := NewHourlyForecastFromUpstream(upstream, HourlyQuery{Start: start}); !errors.Is(err, ErrMalformedPeriod) {
+		t.Fatalf("expected ErrMalformedPeriod, got %v", err)
+	}
+	if _, err := NewHourlyForecastFromUpstream(&ForecastResponse{}, HourlyQuery{}); !errors.Is(err, ErrNoPeriods) {
+		t.Fatalf("expected ErrNoPeriods, got %v", err)
+	}
+}
+
+func TestDegrees(t *testing.T) {
+	celsius := 10.0
+
+	if got := degrees(QuantitativeValue{UnitCode: "wmoUnit:degC", Value: &celsius}, "F"); *got != 50 {
+		t.Fatalf("got %d want 50", *got)
+	}
+	if got := degrees(QuantitativeValue{UnitCode: "wmoUnit:degC", Value: &celsius}, "C"); *got != 10 {
+		t.Fatalf("got %d want 10", *got)
+	}
+	if got := degrees(QuantitativeValue{UnitCode: "wmoUnit:degC"}, "F"); got != nil {
+		t.Fatalf("expected nil, got %d", *got)
+	}
+}
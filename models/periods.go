@@ -0,0 +1,85 @@
+package models
+
+import "math"
+
+// ForecastPeriods is the full multi-day outlook for a location, usually
+// 14 day and night periods covering a week.
+type ForecastPeriods struct {
+	Periods []PeriodForecast `json:"periods"`
+	Units   UnitSystem       `json:"units" example:"us"`
+	Freshness
+}
+
+// PeriodForecast is the forecast for one NWS forecast period.
+type PeriodForecast struct {
+	Number int `json:"number" example:"1"`
+	Period
+	Temperature      int              `json:"temperature" example:"72"`
+	TemperatureUnit  string           `json:"temperature_unit" example:"F"`
+	TemperatureTrend string           `json:"temperature_trend,omitempty" example:"falling"`
+	Characterization Characterization `json:"temperature_characterization"`
+	// ProbabilityOfPrecipitation is a percentage, null when NWS gives none
+	ProbabilityOfPrecipitation *int   `json:"probability_of_precipitation" example:"20"`
+	WindSpeed                  string `json:"wind_speed" example:"5 to 10 mph"`
+	WindDirection              string `json:"wind_direction" example:"SW"`
+	Icon                       string `json:"icon"`
+	ShortForecast              string `json:"short_forecast" example:"Partly Sunny"`
+	DetailedForecast           string `json:"detailed_forecast"`
+}
+
+// NewForecastPeriodsFromUpstream maps every period of an NWS forecast. It
+// fails like NewForecastFromUpstream when there are no periods or one of
+// them is malformed.
+func NewForecastPeriodsFromUpstream(upstream *ForecastResponse) (*ForecastPeriods, error) {
+	if upstream == nil || len(upstream.Properties.Periods) == 0 {
+		return nil, ErrNoPeriods
+	}
+
+	periods := make([]PeriodForecast, 0, len(upstream.Properties.Periods))
+
+	for _, p := range upstream.Properties.Periods {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+
+		periods = append(periods, PeriodForecast{
+			Number:                     p.Number,
+			Period:                     p.summary(),
+			Temperature:                *p.Temperature,
+			TemperatureUnit:            p.TemperatureUnit,
+			TemperatureTrend:           p.TemperatureTrend,
+			Characterization:           characterize(*p.Temperature, p.TemperatureUnit),
+			ProbabilityOfPrecipitation: percent(p.ProbabilityOfPrecipitation),
+			WindSpeed:                  p.WindSpeed,
+			WindDirection:              p.WindDirection,
+			Icon:                       p.Icon,
+			ShortForecast:              p.ShortForecast,
+			DetailedForecast:           p.DetailedForecast,
+		})
+	}
+
+	return &ForecastPeriods{Periods: periods}, nil
+}
+
+// ConvertTo converts the temperatures of every period to system. Wind
+// speeds are left as NWS wrote them.
+func (f *ForecastPeriods) ConvertTo(system UnitSystem) {
+	for i := range f.Periods {
+		p := &f.Periods[i]
+		p.TemperatureUnit = convertTemperatures(p.TemperatureUnit, system, &p.Temperature)
+	}
+
+	f.Units = system
+}
+
+// characterize maps a temperature in unit, "F" or "C", on the Fahrenheit
+// scale MapCharacterizationFromTemp uses.
+func characterize(temp int, unit string) Characterization {
+	fahrenheit, _ := temperatureUnit(unit).Convert(float64(temp), Fahrenheit)
+
+	return MapCharacterizationFromTemp(int(math.Round(fahrenheit)))
+}
+
+func percent(v QuantitativeValue) *int {
+	return v.Round()
+}
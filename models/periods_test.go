@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNewForecastPeriodsFromUpstream(t *testing.T) {
+	body := `{"properties":{"periods":[
+		{"number":1,"name":"Today","startTime":"2024-03-01T06:00:00-06:00","endTime":"2024-03-01T18:00:00-06:00","isDaytime":true,
+		 "temperature":88,"temperatureUnit":"F","temperatureTrend":null,"probabilityOfPrecipitation":{"unitCode":"wmoUnit:percent","value":20},
+		 "windSpeed":"5 to 10 mph","windDirection":"SW","icon":"https://api.weather.gov/icons/land/day/few?size=medium",
+		 "shortForecast":"Sunny","detailedForecast":"Sunny, with a high near 88."},
+		{"number":2,"name":"Tonight","startTime":"2024-03-01T18:00:00-06:00","endTime":"2024-03-02T06:00:00-06:00","isDaytime":false,
+		 "temperature":10,"temperatureUnit":"C","temperatureTrend":"rising","probabilityOfPrecipitation":{"unitCode":"wmoUnit:percent","value":null},
+		 "windSpeed":"5 mph","windDirection":"S","icon":"https://api.weather.gov/icons/land/night/few?size=medium",
+		 "shortForecast":"Mostly Clear","detailedForecast":"Mostly clear, with a low around 10."}
+	]}}`
+
+	var upstream ForecastResponse
+	if err := json.Unmarshal([]byte(body), &upstream); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewForecastPeriodsFromUpstream(&upstream)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Periods) != 2 {
+		t.Fatalf("expected 2 periods, got %d", len(got.Periods))
+	}
+
+	today := got.Periods[0]
+	if today.Number != 1 || today.Name != "Today" || !today.IsDaytime || today.StartTime.Hour() != 6 {
+		t.Fatalf("unexpected period: %+v", today.Period)
+	}
+	if today.Temperature != 88 || today.TemperatureUnit != "F" || today.TemperatureTrend != "" || today.Characterization != Hot {
+		t.Fatalf("unexpected temperature: %+v", today)
+	}
+	if today.ProbabilityOfPrecipitation == nil || *today.ProbabilityOfPrecipitation != 20 {
+		t.Fatalf("unexpected probability of precipitation: %v", today.ProbabilityOfPrecipitation)
+	}
+	if today.WindSpeed != "5 to 10 mph" || today.WindDirection != "SW" || today.Icon == "" || today.DetailedForecast != "Sunny, with a high near 88." {
+		t.Fatalf("unexpected details: %+v", today)
+	}
+
+	tonight := got.Periods[1]
+	if tonight.Characterization != Cold || tonight.TemperatureTrend != "rising" || tonight.ProbabilityOfPrecipitation != nil {
+		t.Fatalf("unexpected period: %+v", tonight)
+	}
+}
+
+func TestNewForecastPeriodsFromUpstream_Invalid(t *testing.T) {
+	temp := 70
+
+	if _, err := NewForecastPeriodsFromUpstream(&ForecastResponse{}); !errors.Is(err, ErrNoPeriods) {
+		t.Fatalf("expected ErrNoPeriods, got %v", err)
+	}
+
+	upstream := &ForecastResponse{Properties: ForecastProperties{Periods: []ForecastPeriod{
+		{Name: "Today", Temperature: &temp, ShortForecast: "Sunny"},
+		{Name: "Tonight", ShortForecast: "Clear"},
+	}}}
+	if _, err := NewForecastPeriodsFromUpstream(upstream); !errors.Is(err, ErrMalformedPeriod) {
+		t.Fatalf("expected ErrMalformedPeriod, got %v", err)
+	}
+}
+
+func TestCharacterize(t *testing.T) {
+	tests := []struct {
+		temp int
+		unit string
+		want Characterization
+	}{
+		{90, "F", Hot},
+		{30, "C", Hot},
+		{21, "C", Moderate},
+		{5, "C", Cold},
+		{50, "", Cold},
+	}
+
+	for _, tc := range tests {
+		if got := characterize(tc.temp, tc.unit); got != tc.want {
+			t.Fatalf("%d%s: got %q want %q", tc.temp, tc.unit, got, tc.want)
+		}
+	}
+}
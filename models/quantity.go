@@ -0,0 +1,177 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Unit is a unit of measure, named by its WMO code without the "wmoUnit:"
+// prefix NWS gives it, e.g. "degC". Units NWS does not send, such as miles
+// per hour, are named in the same style.
+type Unit string
+
+const (
+	Celsius           Unit = "degC"
+	Fahrenheit        Unit = "degF"
+	KilometersPerHour Unit = "km_h-1"
+	MetersPerSecond   Unit = "m_s-1"
+	MilesPerHour      Unit = "mi_h-1"
+	Pascals           Unit = "Pa"
+	Hectopascals      Unit = "hPa"
+	InchesOfMercury   Unit = "inHg"
+	Meters            Unit = "m"
+	Kilometers        Unit = "km"
+	Miles             Unit = "mi"
+	Percent           Unit = "percent"
+	DegreesAngle      Unit = "degree_(angle)"
+)
+
+// ErrUnknownUnit is returned when converting from or to a unit that is not
+// one of the Unit constants.
+var ErrUnknownUnit = errors.New("unknown unit")
+
+// ErrIncompatibleUnits is returned when converting between units of
+// different quantities, such as a temperature to a speed.
+var ErrIncompatibleUnits = errors.New("incompatible units")
+
+// scale relates a unit to the base unit of its quantity: a value v in the
+// unit is v*factor + offset in the base unit.
+type scale struct {
+	quantity string
+	factor   float64
+	offset   float64
+}
+
+var scales = map[Unit]scale{
+	Celsius:           {quantity: "temperature", factor: 1},
+	Fahrenheit:        {quantity: "temperature", factor: 5.0 / 9, offset: -160.0 / 9},
+	MetersPerSecond:   {quantity: "speed", factor: 1},
+	KilometersPerHour: {quantity: "speed", factor: 1 / 3.6},
+	MilesPerHour:      {quantity: "speed", factor: 0.44704},
+	Pascals:           {quantity: "pressure", factor: 1},
+	Hectopascals:      {quantity: "pressure", factor: 100},
+	InchesOfMercury:   {quantity: "pressure", factor: 3386.389},
+	Meters:            {quantity: "length", factor: 1},
+	Kilometers:        {quantity: "length", factor: 1000},
+	Miles:             {quantity: "length", factor: 1609.344},
+	Percent:           {quantity: "percent", factor: 1},
+	DegreesAngle:      {quantity: "angle", factor: 1},
+}
+
+// ParseUnitCode returns the unit of an NWS unit code such as
+// "wmoUnit:degC". The older "unit:" prefix and bare codes are accepted too.
+func ParseUnitCode(code string) (Unit, error) {
+	code = strings.TrimPrefix(code, "wmoUnit:")
+	code = strings.TrimPrefix(code, "unit:")
+
+	if _, ok := scales[Unit(code)]; !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownUnit, code)
+	}
+
+	return Unit(code), nil
+}
+
+// Code returns u as NWS writes unit codes, e.g. "wmoUnit:degC".
+func (u Unit) Code() string {
+	return "wmoUnit:" + string(u)
+}
+
+// Convert converts value from u to unit.
+func (u Unit) Convert(value float64, unit Unit) (float64, error) {
+	from, ok := scales[u]
+
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrUnknownUnit, u)
+	}
+
+	to, ok := scales[unit]
+
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrUnknownUnit, unit)
+	}
+
+	if from.quantity != to.quantity {
+		return 0, fmt.Errorf("%w: %s to %s", ErrIncompatibleUnits, u, unit)
+	}
+
+	if u == unit {
+		return value, nil
+	}
+
+	return (value*from.factor + from.offset - to.offset) / to.factor, nil
+}
+
+// QuantitativeValue is a measurement as NWS sends it, with a WMO unit code
+// such as "wmoUnit:percent" or "wmoUnit:degC". Value is nil when NWS has
+// none, and stays nil through conversion and JSON round trips.
+//
+// Observations also carry a quality control flag; "X" marks a value that
+// failed quality control.
+type QuantitativeValue struct {
+	UnitCode       string   `json:"unitCode"`
+	Value          *float64 `json:"value"`
+	QualityControl string   `json:"qualityControl,omitempty"`
+}
+
+// Unit returns the unit of q.
+func (q QuantitativeValue) Unit() (Unit, error) {
+	return ParseUnitCode(q.UnitCode)
+}
+
+// Rejected reports whether q failed NWS quality control.
+func (q QuantitativeValue) Rejected() bool {
+	return q.QualityControl == "X"
+}
+
+// Convert returns q in unit. A nil value converts to a nil value in unit.
+func (q QuantitativeValue) Convert(unit Unit) (QuantitativeValue, error) {
+	from, err := q.Unit()
+
+	if err != nil {
+		return QuantitativeValue{}, err
+	}
+
+	converted := QuantitativeValue{UnitCode: unit.Code(), QualityControl: q.QualityControl}
+
+	if q.Value == nil {
+		if _, err := from.Convert(0, unit); err != nil {
+			return QuantitativeValue{}, err
+		}
+
+		return converted, nil
+	}
+
+	value, err := from.Convert(*q.Value, unit)
+
+	if err != nil {
+		return QuantitativeValue{}, err
+	}
+
+	converted.Value = &value
+
+	return converted, nil
+}
+
+// Round returns the value of q rounded to the nearest integer, or nil when
+// there is none.
+func (q QuantitativeValue) Round() *int {
+	if q.Value == nil {
+		return nil
+	}
+
+	rounded := int(math.Round(*q.Value))
+
+	return &rounded
+}
+
+// temperatureUnit maps the "F" and "C" NWS uses for forecast temperatures
+// to a Unit.
+func temperatureUnit(unit string) Unit {
+	if unit == "C" {
+		return Celsius
+	}
+
+	return Fahrenheit
+}
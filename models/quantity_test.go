@@ -0,0 +1,126 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseUnitCode(t *testing.T) {
+	tests := map[string]Unit{
+		"wmoUnit:degC":           Celsius,
+		"wmoUnit:degF":           Fahrenheit,
+		"wmoUnit:km_h-1":         KilometersPerHour,
+		"wmoUnit:m_s-1":          MetersPerSecond,
+		"wmoUnit:Pa":             Pascals,
+		"wmoUnit:m":              Meters,
+		"wmoUnit:percent":        Percent,
+		"wmoUnit:degree_(angle)": DegreesAngle,
+		"unit:degC":              Celsius,
+		"degF":                   Fahrenheit,
+	}
+
+	for code, want := range tests {
+		got, err := ParseUnitCode(code)
+		if err != nil || got != want {
+			t.Fatalf("%s: got %q, %v want %q", code, got, err, want)
+		}
+	}
+
+	if _, err := ParseUnitCode("wmoUnit:furlong"); !errors.Is(err, ErrUnknownUnit) {
+		t.Fatalf("unknown unit: got %v", err)
+	}
+}
+
+func TestUnit_Convert(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to Unit
+		want     float64
+	}{
+		{100, Celsius, Fahrenheit, 212},
+		{-40, Fahrenheit, Celsius, -40},
+		{32, Fahrenheit, Celsius, 0},
+		{36, KilometersPerHour, MetersPerSecond, 10},
+		{10, MetersPerSecond, MilesPerHour, 22.369},
+		{101325, Pascals, InchesOfMercury, 29.921},
+		{101325, Pascals, Hectopascals, 1013.25},
+		{16093.44, Meters, Miles, 10},
+		{5, Kilometers, Meters, 5000},
+		{45, Percent, Percent, 45},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.from.Convert(tt.value, tt.to)
+		if err != nil || math.Abs(got-tt.want) > 0.001 {
+			t.Fatalf("%g %s to %s: got %g, %v want %g", tt.value, tt.from, tt.to, got, err, tt.want)
+		}
+	}
+
+	if _, err := Celsius.Convert(1, KilometersPerHour); !errors.Is(err, ErrIncompatibleUnits) {
+		t.Fatalf("temperature to speed: got %v", err)
+	}
+	if _, err := Unit("furlong").Convert(1, Meters); !errors.Is(err, ErrUnknownUnit) {
+		t.Fatalf("unknown unit: got %v", err)
+	}
+}
+
+func TestQuantitativeValue_Convert(t *testing.T) {
+	value := 20.0
+	q := QuantitativeValue{UnitCode: "wmoUnit:degC", Value: &value, QualityControl: "V"}
+
+	got, err := q.Convert(Fahrenheit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UnitCode != "wmoUnit:degF" || got.Value == nil || *got.Value != 68 || got.QualityControl != "V" {
+		t.Fatalf("unexpected conversion: %+v", got)
+	}
+	if *q.Value != 20 {
+		t.Fatalf("conversion changed the original value to %g", *q.Value)
+	}
+
+	null, err := QuantitativeValue{UnitCode: "wmoUnit:km_h-1"}.Convert(MilesPerHour)
+	if err != nil || null.Value != nil || null.UnitCode != "wmoUnit:mi_h-1" {
+		t.Fatalf("null value: got %+v, %v", null, err)
+	}
+
+	if _, err := (QuantitativeValue{UnitCode: "wmoUnit:km_h-1"}).Convert(Celsius); !errors.Is(err, ErrIncompatibleUnits) {
+		t.Fatalf("null value in incompatible unit: got %v", err)
+	}
+}
+
+func TestQuantitativeValue_JSON(t *testing.T) {
+	tests := []string{
+		`{"unitCode":"wmoUnit:degC","value":21.7,"qualityControl":"V"}`,
+		`{"unitCode":"wmoUnit:km_h-1","value":null,"qualityControl":"Z"}`,
+		`{"unitCode":"wmoUnit:percent","value":null}`,
+	}
+
+	for _, in := range tests {
+		var q QuantitativeValue
+		if err := json.Unmarshal([]byte(in), &q); err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := json.Marshal(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != in {
+			t.Fatalf("round trip: got %s want %s", out, in)
+		}
+	}
+}
+
+func TestQuantitativeValue_Round(t *testing.T) {
+	value := 21.5
+
+	if got := (QuantitativeValue{Value: &value}).Round(); got == nil || *got != 22 {
+		t.Fatalf("got %v want 22", got)
+	}
+	if got := (QuantitativeValue{}).Round(); got != nil {
+		t.Fatalf("null value: got %d", *got)
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// UnitSystem is the system of units responses are given in.
+type UnitSystem string
+
+const (
+	// US units: °F, mph, inches of mercury and miles.
+	US UnitSystem = "us"
+	// SI units as NWS gives them: °C, km/h, pascals and meters.
+	SI UnitSystem = "si"
+)
+
+// ErrUnknownUnitSystem is returned by ParseUnitSystem for anything but the
+// unit systems it accepts.
+var ErrUnknownUnitSystem = errors.New(`must be "us", "si" or "metric"`)
+
+// ParseUnitSystem parses "us", "si" or "metric", which is another name for
+// "si", ignoring case.
+func ParseUnitSystem(s string) (UnitSystem, error) {
+	switch strings.ToLower(s) {
+	case "us":
+		return US, nil
+	case "si", "metric":
+		return SI, nil
+	}
+
+	return "", fmt.Errorf("invalid units %q: %w", s, ErrUnknownUnitSystem)
+}
+
+// Temperature returns the unit temperatures are given in.
+func (s UnitSystem) Temperature() Unit {
+	if s == SI {
+		return Celsius
+	}
+
+	return Fahrenheit
+}
+
+// TemperatureUnit returns the unit temperatures are given in as NWS names
+// it in forecasts, "F" or "C".
+func (s UnitSystem) TemperatureUnit() string {
+	if s == SI {
+		return "C"
+	}
+
+	return "F"
+}
+
+// Speed returns the unit wind speeds are given in.
+func (s UnitSystem) Speed() Unit {
+	if s == SI {
+		return KilometersPerHour
+	}
+
+	return MilesPerHour
+}
+
+// Pressure returns the unit pressures are given in.
+func (s UnitSystem) Pressure() Unit {
+	if s == SI {
+		return Pascals
+	}
+
+	return InchesOfMercury
+}
+
+// Length returns the unit distances such as visibility are given in.
+func (s UnitSystem) Length() Unit {
+	if s == SI {
+		return Meters
+	}
+
+	return Miles
+}
+
+// unitFor returns the unit of system for values of the same quantity as
+// unit. Quantities every system shares, such as percentages, keep theirs.
+func (s UnitSystem) unitFor(unit Unit) Unit {
+	switch scales[unit].quantity {
+	case "temperature":
+		return s.Temperature()
+	case "speed":
+		return s.Speed()
+	case "pressure":
+		return s.Pressure()
+	case "length":
+		return s.Length()
+	}
+
+	return unit
+}
+
+// convertTemperature converts a whole number temperature given in unit,
+// "F" or "C", to system, returning it and its new unit. Temperatures
+// already in system's unit, or in a unit it does not know, are returned as
+// they are.
+func convertTemperature(temp int, unit string, system UnitSystem) (int, string) {
+	if unit == system.TemperatureUnit() || (unit != "F" && unit != "C") {
+		return temp, unit
+	}
+
+	converted, _ := temperatureUnit(unit).Convert(float64(temp), system.Temperature())
+
+	return int(math.Round(converted)), system.TemperatureUnit()
+}
+
+// convertTemperatures converts temperatures given in unit to system in
+// place, returning their new unit.
+func convertTemperatures(unit string, system UnitSystem, temps ...*int) string {
+	for _, temp := range temps {
+		if temp != nil {
+			*temp, _ = convertTemperature(*temp, unit, system)
+		}
+	}
+
+	_, converted := convertTemperature(0, unit, system)
+
+	return converted
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseUnitSystem(t *testing.T) {
+	tests := map[string]UnitSystem{"us": US, "US": US, "si": SI, "metric": SI, "Metric": SI}
+
+	for value, want := range tests {
+		if got, err := ParseUnitSystem(value); err != nil || got != want {
+			t.Fatalf("%q: got %q, %v want %q", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"", "imperial", "kelvin"} {
+		if _, err := ParseUnitSystem(value); !errors.Is(err, ErrUnknownUnitSystem) {
+			t.Fatalf("%q: expected ErrUnknownUnitSystem, got %v", value, err)
+		}
+	}
+}
+
+func TestConvertTo(t *testing.T) {
+	high, low, dewpoint := 77, 50, 15
+
+	daily := &DailyForecast{Days: []DayForecast{{
+		High: &high, Low: &low, TemperatureUnit: "F",
+		Day: &DaySummary{Temperature: 77}, Night: &DaySummary{Temperature: 50},
+	}}}
+	daily.ConvertTo(SI)
+
+	day := daily.Days[0]
+	if *day.High != 25 || *day.Low != 10 || day.Day.Temperature != 25 || day.Night.Temperature != 10 || day.TemperatureUnit != "C" || daily.Units != SI {
+		t.Fatalf("unexpected day: %+v", day)
+	}
+
+	hourly := &HourlyForecast{Hours: []HourForecast{{Temperature: 20, TemperatureUnit: "C", Dewpoint: &dewpoint}}}
+	hourly.ConvertTo(US)
+
+	if h := hourly.Hours[0]; h.Temperature != 68 || *h.Dewpoint != 59 || h.TemperatureUnit != "F" || hourly.Units != US {
+		t.Fatalf("unexpected hour: %+v", h)
+	}
+
+	// Temperatures already in the unit system are left alone
+	periods := &ForecastPeriods{Periods: []PeriodForecast{{Temperature: 72, TemperatureUnit: "F"}}}
+	periods.ConvertTo(US)
+
+	if p := periods.Periods[0]; p.Temperature != 72 || p.TemperatureUnit != "F" || periods.Units != US {
+		t.Fatalf("unexpected period: %+v", p)
+	}
+}
@@ -1,11 +1,102 @@
 package models
 
+import "time"
+
 type ForecastResponse struct {
+	Properties ForecastProperties `json:"properties"`
+}
+
+type ForecastProperties struct {
+	Periods []ForecastPeriod `json:"periods"`
+}
+
+// ForecastPeriod is one period of an NWS forecast. Temperature is a
+// pointer so that a period without one can be told apart from 0°.
+type ForecastPeriod struct {
+	Number                     int               `json:"number"`
+	Name                       string            `json:"name"`
+	StartTime                  time.Time         `json:"startTime"`
+	EndTime                    time.Time         `json:"endTime"`
+	IsDaytime                  bool              `json:"isDaytime"`
+	Temperature                *int              `json:"temperature"`
+	TemperatureUnit            string            `json:"temperatureUnit"`
+	TemperatureTrend           string            `json:"temperatureTrend"`
+	ProbabilityOfPrecipitation QuantitativeValue `json:"probabilityOfPrecipitation"`
+	Dewpoint                   QuantitativeValue `json:"dewpoint"`
+	RelativeHumidity           QuantitativeValue `json:"relativeHumidity"`
+	WindSpeed                  string            `json:"windSpeed"`
+	WindDirection              string            `json:"windDirection"`
+	Icon                       string            `json:"icon"`
+	ShortForecast              string            `json:"shortForecast"`
+	DetailedForecast           string            `json:"detailedForecast"`
+}
+
+// AlertsResponse is the GeoJSON collection of alerts NWS returns from
+// /alerts/active.
+type AlertsResponse struct {
+	Features []AlertFeature `json:"features"`
+}
+
+type AlertFeature struct {
+	Properties AlertProperties `json:"properties"`
+}
+
+// AlertProperties describes one NWS alert, following the Common Alerting
+// Protocol.
+type AlertProperties struct {
+	ID            string    `json:"id"`
+	AreaDesc      string    `json:"areaDesc"`
+	AffectedZones []string  `json:"affectedZones"`
+	Sent          time.Time `json:"sent"`
+	Effective     time.Time `json:"effective"`
+	Onset         time.Time `json:"onset"`
+	Expires       time.Time `json:"expires"`
+	Ends          time.Time `json:"ends"`
+	Status        string    `json:"status"`
+	MessageType   string    `json:"messageType"`
+	Severity      string    `json:"severity"`
+	Certainty     string    `json:"certainty"`
+	Urgency       string    `json:"urgency"`
+	Event         string    `json:"event"`
+	SenderName    string    `json:"senderName"`
+	Headline      string    `json:"headline"`
+	Description   string    `json:"description"`
+	Instruction   string    `json:"instruction"`
+}
+
+// StationsResponse is the GeoJSON collection of observation stations NWS
+// links to from /points.
+type StationsResponse struct {
+	Features []StationFeature `json:"features"`
+}
+
+// StationFeature is an observation station. ID is its URL and Geometry a
+// GeoJSON point, so its coordinates are longitude then latitude.
+type StationFeature struct {
+	ID       string `json:"id"`
+	Geometry struct {
+		Coordinates []float64 `json:"coordinates"`
+	} `json:"geometry"`
 	Properties struct {
-		Periods []struct {
-			Name          string `json:"name"`
-			Temperature   int    `json:"temperature"`
-			ShortForecast string `json:"shortForecast"`
-		} `json:"periods"`
+		StationIdentifier string `json:"stationIdentifier"`
+		Name              string `json:"name"`
 	} `json:"properties"`
 }
+
+// ObservationResponse is an observation from a station.
+type ObservationResponse struct {
+	Properties ObservationProperties `json:"properties"`
+}
+
+type ObservationProperties struct {
+	Timestamp          time.Time         `json:"timestamp"`
+	TextDescription    string            `json:"textDescription"`
+	Temperature        QuantitativeValue `json:"temperature"`
+	Dewpoint           QuantitativeValue `json:"dewpoint"`
+	RelativeHumidity   QuantitativeValue `json:"relativeHumidity"`
+	WindDirection      QuantitativeValue `json:"windDirection"`
+	WindSpeed          QuantitativeValue `json:"windSpeed"`
+	WindGust           QuantitativeValue `json:"windGust"`
+	BarometricPressure QuantitativeValue `json:"barometricPressure"`
+	Visibility         QuantitativeValue `json:"visibility"`
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// origin is the scheme and host, including any port, that a followed link
+// must have.
+type origin struct {
+	scheme string
+	host   string
+}
+
+func parseOrigin(rawURL string) (origin, bool) {
+	u, err := url.Parse(rawURL)
+
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return origin{}, false
+	}
+
+	return origin{scheme: u.Scheme, host: strings.ToLower(u.Host)}, true
+}
+
+// checkLink reports whether link, taken from an NWS response or a redirect,
+// may be fetched. Only links to the base URL's origin and those added with
+// WithAllowedOrigins are, so a spoofed or compromised upstream cannot make
+// us fetch internal URLs.
+func (n *nwsAPI) checkLink(link string) error {
+	u, err := url.Parse(link)
+
+	if err != nil || u.User != nil {
+		return &LinkNotAllowedError{URL: link}
+	}
+
+	want := origin{scheme: u.Scheme, host: strings.ToLower(u.Host)}
+
+	for _, allowed := range n.allowedOrigins {
+		if allowed == want {
+			return nil
+		}
+	}
+
+	return &LinkNotAllowedError{URL: link}
+}
+
+// checkRedirects wraps client so that redirects are held to the same
+// allowlist as links.
+func (n *nwsAPI) checkRedirects(client *http.Client) *http.Client {
+	checked := *client
+	next := client.CheckRedirect
+
+	checked.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := n.checkLink(req.URL.String()); err != nil {
+			return err
+		}
+
+		if next != nil {
+			return next(req, via)
+		}
+
+		// Same limit as the http.Client default
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+
+		return nil
+	}
+
+	return &checked
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting NWS while the circuit
+// breaker considers it down.
+var ErrCircuitOpen = errors.New("NWS is unavailable: circuit breaker is open")
+
+// BreakerPolicy controls the circuit breaker around NWS. After
+// FailureThreshold consecutive failed requests the circuit opens and
+// requests fail fast with ErrCircuitOpen. Once CoolDown has passed it is
+// half-open: up to HalfOpenRequests trial requests are let through, and
+// the first one to succeed closes the circuit while a failure opens it
+// again.
+type BreakerPolicy struct {
+	// FailureThreshold of zero or less disables the breaker.
+	FailureThreshold int
+	CoolDown         time.Duration
+	HalfOpenRequests int
+}
+
+// DefaultBreakerPolicy opens after 5 consecutive failures and probes NWS
+// with a single request every 30 seconds while it is open.
+var DefaultBreakerPolicy = BreakerPolicy{
+	FailureThreshold: 5,
+	CoolDown:         30 * time.Second,
+	HalfOpenRequests: 1,
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+type breaker struct {
+	mu       sync.Mutex
+	policy   BreakerPolicy
+	now      func() time.Time
+	state    breakerState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+func newBreaker(policy BreakerPolicy, now func() time.Time) *breaker {
+	return &breaker{policy: policy, now: now}
+}
+
+// allow reports whether a request may be sent upstream. Every allowed
+// request must be followed by a call to record with its outcome.
+func (b *breaker) allow() error {
+	if b.policy.FailureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen && b.now().Sub(b.openedAt) >= b.policy.CoolDown {
+		b.setState(breakerHalfOpen)
+		b.trials = 0
+	}
+
+	switch b.state {
+	case breakerOpen:
+		return ErrCircuitOpen
+	case breakerHalfOpen:
+		if b.trials >= max(b.policy.HalfOpenRequests, 1) {
+			return ErrCircuitOpen
+		}
+		b.trials++
+	}
+
+	return nil
+}
+
+// record updates the breaker with the outcome of an allowed request.
+func (b *breaker) record(failed bool) {
+	if b.policy.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.failures = 0
+		b.setState(breakerClosed)
+		return
+	}
+
+	b.failures++
+
+	if b.state == breakerHalfOpen || b.failures >= b.policy.FailureThreshold {
+		b.openedAt = b.now()
+		b.setState(breakerOpen)
+	}
+}
+
+func (b *breaker) currentState() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// setState moves the breaker to state, logging transitions. b.mu must be
+// held.
+func (b *breaker) setState(state breakerState) {
+	if b.state != state {
+		log.Printf("NWS circuit breaker %s -> %s", b.state, state)
+	}
+
+	b.state = state
+}
+
+// upstreamFailed reports whether an outcome suggests NWS itself is in
+// trouble, as opposed to a request it rightly rejected.
+func upstreamFailed(resp *upstreamResponse, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return resp.statusCode >= 500 || resp.statusCode == http.StatusTooManyRequests
+}
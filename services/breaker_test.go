@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newBreaker(BreakerPolicy{FailureThreshold: 3, CoolDown: time.Minute, HalfOpenRequests: 1}, func() time.Time { return now })
+
+	for i := 0; i < 2; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b.record(true)
+	}
+
+	// a success resets the count of consecutive failures
+	b.allow()
+	b.record(false)
+
+	for i := 0; i < 3; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b.record(true)
+	}
+
+	if b.currentState() != breakerOpen {
+		t.Fatalf("expected open breaker, got %s", b.currentState())
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestBreaker_HalfOpenProbes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newBreaker(BreakerPolicy{FailureThreshold: 1, CoolDown: time.Minute, HalfOpenRequests: 2}, func() time.Time { return now })
+
+	b.allow()
+	b.record(true)
+
+	now = now.Add(59 * time.Second)
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected breaker to stay open during cool-down, got %v", err)
+	}
+
+	now = now.Add(time.Second)
+	for i := 0; i < 2; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("trial %d: unexpected error: %v", i, err)
+		}
+	}
+	if b.currentState() != breakerHalfOpen {
+		t.Fatalf("expected half-open breaker, got %s", b.currentState())
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected trials to be limited, got %v", err)
+	}
+
+	// a failed trial opens the circuit again for another cool-down
+	b.record(true)
+	if b.currentState() != breakerOpen {
+		t.Fatalf("expected open breaker after failed trial, got %s", b.currentState())
+	}
+
+	now = now.Add(time.Minute)
+	if err := b.allow(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b.record(false)
+
+	if b.currentState() != breakerClosed {
+		t.Fatalf("expected closed breaker after successful trial, got %s", b.currentState())
+	}
+	if err := b.allow(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBreaker_Disabled(t *testing.T) {
+	t.Parallel()
+
+	b := newBreaker(BreakerPolicy{}, time.Now)
+
+	for i := 0; i < 100; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b.record(true)
+	}
+}
+
+func TestUpstreamFailed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		resp *upstreamResponse
+		err  error
+		want bool
+	}{
+		{"network error", nil, errors.New("reset"), true},
+		{"ok", &upstreamResponse{statusCode: 200}, nil, false},
+		{"not modified", &upstreamResponse{statusCode: 304}, nil, false},
+		{"not found", &upstreamResponse{statusCode: 404}, nil, false},
+		{"rate limited", &upstreamResponse{statusCode: 429}, nil, true},
+		{"server error", &upstreamResponse{statusCode: 503}, nil, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := upstreamFailed(tc.resp, tc.err); got != tc.want {
+				t.Fatalf("got %v want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFetch_FailsFastWhileCircuitIsOpen(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	var healthy atomic.Bool
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, `{}`)
+	}))
+	defer ts.Close()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewClient(
+		WithRetryPolicy(RetryPolicy{}),
+		WithBreakerPolicy(BreakerPolicy{FailureThreshold: 2, CoolDown: time.Minute, HalfOpenRequests: 1}),
+	).(*nwsAPI)
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.fetch(context.Background(), ts.URL, nil); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected upstream error, got %v", err)
+		}
+	}
+
+	if _, err := c.fetch(context.Background(), ts.URL, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected open circuit not to reach upstream, got %d requests", got)
+	}
+
+	healthy.Store(true)
+	now = now.Add(time.Minute)
+
+	if _, err := c.fetch(context.Background(), ts.URL, nil); err != nil {
+		t.Fatalf("expected trial request to succeed, got %v", err)
+	}
+	if c.breaker.currentState() != breakerClosed {
+		t.Fatalf("expected circuit to close, got %s", c.breaker.currentState())
+	}
+}
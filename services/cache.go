@@ -0,0 +1,152 @@
+package services
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Cache stores upstream responses keyed by URL. Implementations must be
+// safe for concurrent use.
+type Cache interface {
+	// Get returns the entry stored under key, whether it is fresh or not.
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+	Remove(key string)
+	Len() int
+}
+
+// CacheEntry is an upstream response along with what is needed to decide
+// whether it is still fresh and to revalidate it once it is not.
+type CacheEntry struct {
+	Body         json.RawMessage `json:"body"`
+	Stored       time.Time       `json:"stored"`
+	Expires      time.Time       `json:"expires"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+
+	// Value is Body decoded, kept only in memory so hits skip decoding.
+	Value any `json:"-"`
+}
+
+// fresh reports whether the entry can be served without asking upstream.
+func (e CacheEntry) fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+// usableUntil reports whether the entry is at most window past its expiry.
+func (e CacheEntry) usableUntil(now time.Time, window time.Duration) bool {
+	return window > 0 && now.Before(e.Expires.Add(window))
+}
+
+// CacheStats reports how the upstream response cache has been used.
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+type memoryCacheEntry struct {
+	key   string
+	entry CacheEntry
+}
+
+// MemoryCache is an in-memory LRU Cache. Expired entries are kept until
+// evicted so their validators can be used to revalidate them.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+	evictions  uint64
+}
+
+// NewMemoryCache returns a MemoryCache holding at most maxEntries entries.
+// A cache with a non-positive size stores nothing.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (c *MemoryCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+
+	if !ok {
+		return CacheEntry{}, false
+	}
+
+	c.order.MoveToFront(elem)
+
+	return elem.Value.(*memoryCacheEntry).entry, true
+}
+
+// Set stores entry under key, evicting the least recently used entries
+// when the cache is full.
+func (c *MemoryCache) Set(key string, entry CacheEntry) {
+	if c.maxEntries <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*memoryCacheEntry).entry = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryCacheEntry{key: key, entry: entry})
+
+	for c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+		c.evictions++
+	}
+}
+
+func (c *MemoryCache) Remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Evictions returns how many entries were dropped to stay within size.
+func (c *MemoryCache) Evictions() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.evictions
+}
+
+// each calls fn for every entry from least to most recently used.
+func (c *MemoryCache) each(fn func(key string, entry CacheEntry)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.order.Back(); elem != nil; elem = elem.Prev() {
+		item := elem.Value.(*memoryCacheEntry)
+		fn(item.key, item.entry)
+	}
+}
+
+func (c *MemoryCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*memoryCacheEntry).key)
+}
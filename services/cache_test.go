@@ -0,0 +1,114 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_GetSet(t *testing.T) {
+	t.Parallel()
+
+	c := NewMemoryCache(10)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+
+	c.Set("a", CacheEntry{Value: 1, ETag: `"v1"`})
+
+	got, ok := c.Get("a")
+	if !ok || got.Value != 1 || got.ETag != `"v1"` {
+		t.Fatalf("unexpected entry: %#v ok=%v", got, ok)
+	}
+
+	if c.Len() != 1 {
+		t.Fatalf("unexpected length: %d", c.Len())
+	}
+}
+
+func TestCacheEntry_Fresh(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := CacheEntry{Expires: now.Add(time.Minute)}
+
+	if !entry.fresh(now.Add(59 * time.Second)) {
+		t.Fatal("expected entry to be fresh before expiry")
+	}
+	if entry.fresh(now.Add(time.Minute)) {
+		t.Fatal("expected entry to be stale at expiry")
+	}
+}
+
+func TestCacheEntry_UsableUntil(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := CacheEntry{Expires: now}
+
+	if !entry.usableUntil(now.Add(59*time.Second), time.Minute) {
+		t.Fatal("expected entry to be usable inside the window")
+	}
+	if entry.usableUntil(now.Add(time.Minute), time.Minute) {
+		t.Fatal("expected entry to be unusable at the end of the window")
+	}
+	if entry.usableUntil(now.Add(-time.Second), 0) {
+		t.Fatal("expected a zero window to disable stale use")
+	}
+}
+
+func TestMemoryCache_KeepsExpiredEntriesForRevalidation(t *testing.T) {
+	t.Parallel()
+
+	c := NewMemoryCache(10)
+	c.Set("a", CacheEntry{Value: 1, Expires: time.Now().Add(-time.Minute), ETag: `"v1"`})
+
+	got, ok := c.Get("a")
+	if !ok || got.ETag != `"v1"` {
+		t.Fatalf("expected expired entry to be kept, got %#v ok=%v", got, ok)
+	}
+
+	c.Remove("a")
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected removed entry to be gone")
+	}
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	c := NewMemoryCache(2)
+
+	c.Set("a", CacheEntry{Value: 1})
+	c.Set("b", CacheEntry{Value: 2})
+
+	// touch a so b becomes the least recently used entry
+	c.Get("a")
+	c.Set("c", CacheEntry{Value: 3})
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected a to survive eviction")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatal("expected c to be cached")
+	}
+
+	if c.Evictions() != 1 || c.Len() != 2 {
+		t.Fatalf("unexpected evictions=%d len=%d", c.Evictions(), c.Len())
+	}
+}
+
+func TestMemoryCache_Disabled(t *testing.T) {
+	t.Parallel()
+
+	c := NewMemoryCache(0)
+	c.Set("a", CacheEntry{Value: 1})
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected zero-size cache to store nothing")
+	}
+}
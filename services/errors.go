@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// UpstreamStatusError is returned when NWS answers with a status other
+// than 200 or 304. The problem fields are filled in from the RFC 7807
+// body NWS sends with most errors.
+type UpstreamStatusError struct {
+	URL           string
+	StatusCode    int
+	Type          string
+	Title         string
+	Detail        string
+	CorrelationID string
+}
+
+func (e *UpstreamStatusError) Error() string {
+	if e.Detail != "" {
+		return e.Detail
+	}
+
+	if e.Title != "" {
+		return e.Title
+	}
+
+	return fmt.Sprintf("non 200 response from upstream: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// NetworkError is returned when NWS could not be reached or the response
+// could not be read.
+type NetworkError struct {
+	URL string
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned when a successful NWS response is not the JSON
+// we expect.
+type DecodeError struct {
+	URL string
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("unable to decode response from %s: %v", e.URL, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DataUnavailableError is returned when NWS answers successfully but
+// without usable forecast data, such as during grid maintenance.
+type DataUnavailableError struct {
+	URL string
+	Err error
+}
+
+func (e *DataUnavailableError) Error() string {
+	return fmt.Sprintf("forecast data from %s is unavailable: %v", e.URL, e.Err)
+}
+
+func (e *DataUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// ErrMissingLink is wrapped in a DataUnavailableError when the /points
+// metadata for a location lacks a link, as it does for locations NWS has
+// no hourly forecast or stations for.
+var ErrMissingLink = errors.New("points metadata has no link")
+
+// LinkNotAllowedError is returned instead of fetching a link from an NWS
+// response, or a redirect, that leaves the allowed origins.
+type LinkNotAllowedError struct {
+	URL string
+}
+
+func (e *LinkNotAllowedError) Error() string {
+	return fmt.Sprintf("refusing to follow link to %s: origin is not allowed", e.URL)
+}
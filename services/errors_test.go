@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rmccullagh/weather-api/models"
+)
+
+func TestUpstreamStatusError_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  *UpstreamStatusError
+		want string
+	}{
+		{"detail", &UpstreamStatusError{StatusCode: 404, Title: "Not Found", Detail: "no such point"}, "no such point"},
+		{"title", &UpstreamStatusError{StatusCode: 404, Title: "Not Found"}, "Not Found"},
+		{"status only", &UpstreamStatusError{StatusCode: 502}, "non 200 response from upstream: 502 Bad Gateway"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.err.Error(); got != tc.want {
+				t.Fatalf("got %q want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFetch_UpstreamStatusError(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{
+			"correlationId": "abc123",
+			"title": "Data Unavailable For Requested Point",
+			"type": "https://api.weather.gov/problems/InvalidPoint",
+			"status": 404,
+			"detail": "Unable to provide data for requested point 10,10",
+			"instance": "https://api.weather.gov/requests/abc123"
+		}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithHTTPClient(ts.Client())).(*nwsAPI)
+	_, err := c.fetch(context.Background(), ts.URL+"/points/10,10", nil)
+
+	var statusErr *UpstreamStatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected UpstreamStatusError, got %T: %v", err, err)
+	}
+
+	want := UpstreamStatusError{
+		URL:           ts.URL + "/points/10,10",
+		StatusCode:    http.StatusNotFound,
+		Type:          "https://api.weather.gov/problems/InvalidPoint",
+		Title:         "Data Unavailable For Requested Point",
+		Detail:        "Unable to provide data for requested point 10,10",
+		CorrelationID: "abc123",
+	}
+	if *statusErr != want {
+		t.Fatalf("got %#v want %#v", *statusErr, want)
+	}
+}
+
+func TestFetch_NetworkError(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	c := NewClient(WithTransport(transport), WithRetryPolicy(RetryPolicy{})).(*nwsAPI)
+
+	_, err := c.fetch(context.Background(), "https://api.weather.gov/points/1,2", nil)
+
+	var networkErr *NetworkError
+	if !errors.As(err, &networkErr) {
+		t.Fatalf("expected NetworkError, got %T: %v", err, err)
+	}
+	if networkErr.URL != "https://api.weather.gov/points/1,2" || !strings.Contains(networkErr.Error(), "connection refused") {
+		t.Fatalf("unexpected error: %#v", networkErr)
+	}
+}
+
+func TestCachedGet_DecodeError(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"properties":`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithHTTPClient(ts.Client())).(*nwsAPI)
+	_, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected DecodeError, got %T: %v", err, err)
+	}
+	if decodeErr.URL != ts.URL || !strings.Contains(decodeErr.Error(), "unable to decode response") {
+		t.Fatalf("unexpected error: %#v", decodeErr)
+	}
+}
+
+func TestNwsAPI_GetForecast_ErrorsAs(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 404,
+			Body:       io.NopCloser(strings.NewReader(`{"title":"Not Found","detail":"bad point","correlationId":"xyz"}`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	_, err := NewClient(WithTransport(transport)).GetForecast(context.Background(), testPoint, "")
+
+	var statusErr *UpstreamStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 404 || statusErr.CorrelationID != "xyz" {
+		t.Fatalf("expected UpstreamStatusError from GetForecast, got %#v", err)
+	}
+	if statusErr.URL != "https://api.weather.gov/points/1,2" {
+		t.Fatalf("unexpected URL: %q", statusErr.URL)
+	}
+}
+
+func TestNwsAPI_GetForecast_NoPeriods(t *testing.T) {
+	t.Parallel()
+
+	var forecastCalls atomic.Int32
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+		if req.URL.Path == "/forecast/1" {
+			forecastCalls.Add(1)
+			body = `{"properties":{"periods":[]}}`
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	c := NewClient(WithTransport(transport))
+
+	for range 2 {
+		f, err := c.GetForecast(context.Background(), testPoint, "")
+
+		var unavailableErr *DataUnavailableError
+		if !errors.As(err, &unavailableErr) || !errors.Is(err, models.ErrNoPeriods) {
+			t.Fatalf("expected DataUnavailableError, got %T: %v", err, err)
+		}
+		if unavailableErr.URL != "https://api.weather.gov/forecast/1" || f != nil {
+			t.Fatalf("unexpected result: %#v, %v", unavailableErr, f)
+		}
+	}
+
+	// The unusable forecast must not be served from the cache
+	if got := forecastCalls.Load(); got != 2 {
+		t.Fatalf("forecast fetched %d times, want 2", got)
+	}
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// fileCacheCompactSlack is how many superseded records the log may hold
+// beyond twice the number of live entries before it is compacted.
+const fileCacheCompactSlack = 64
+
+// fileCacheRecord is one line of the cache log. A record without an entry
+// removes the key.
+type fileCacheRecord struct {
+	Key   string      `json:"key"`
+	Entry *CacheEntry `json:"entry,omitempty"`
+}
+
+// FileCache is a Cache that serves entries from memory and persists every
+// change to an append-only log of JSON lines, so cached points metadata and
+// forecasts survive a restart. Lines that cannot be decoded, such as one
+// left half-written by a crash, are skipped on load. The log is rewritten
+// with only the live entries once it has grown well past them.
+type FileCache struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	memory  *MemoryCache
+	records int
+}
+
+// NewFileCache loads the cache persisted at path, creating it if needed,
+// and keeps at most maxEntries entries.
+func NewFileCache(path string, maxEntries int) (*FileCache, error) {
+	c := &FileCache{path: path, memory: NewMemoryCache(maxEntries)}
+
+	corrupt, err := c.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if corrupt > 0 {
+		log.Printf("skipped %d corrupt records in cache file %s", corrupt, path)
+	}
+
+	// Rewriting on start drops corrupt lines before anything is appended
+	// after them, and trims entries that no longer fit in maxEntries
+	if err := c.compact(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *FileCache) Get(key string) (CacheEntry, bool) {
+	return c.memory.Get(key)
+}
+
+func (c *FileCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.memory.Set(key, entry)
+	c.append(fileCacheRecord{Key: key, Entry: &entry})
+}
+
+func (c *FileCache) Remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.memory.Remove(key)
+	c.append(fileCacheRecord{Key: key})
+}
+
+func (c *FileCache) Len() int {
+	return c.memory.Len()
+}
+
+// Close closes the underlying log file.
+func (c *FileCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return nil
+	}
+
+	err := c.file.Close()
+	c.file = nil
+
+	return err
+}
+
+// load replays the log into memory and returns how many lines it skipped.
+func (c *FileCache) load() (int, error) {
+	file, err := os.Open(c.path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	corrupt := 0
+
+	for {
+		line, err := reader.ReadBytes('\n')
+
+		if len(line) > 0 {
+			var record fileCacheRecord
+
+			if json.Unmarshal(line, &record) != nil || record.Key == "" {
+				corrupt++
+			} else if record.Entry == nil {
+				c.memory.Remove(record.Key)
+			} else {
+				c.memory.Set(record.Key, *record.Entry)
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return corrupt, nil
+		}
+
+		if err != nil {
+			return corrupt, err
+		}
+	}
+}
+
+// append writes record to the log and compacts it when it has grown too
+// large. Failures are logged; the in-memory cache keeps working without
+// persistence. c.mu must be held.
+func (c *FileCache) append(record fileCacheRecord) {
+	if c.file == nil {
+		return
+	}
+
+	line, err := json.Marshal(record)
+
+	if err != nil {
+		log.Printf("unable to encode cache record for %s: %v", record.Key, err)
+		return
+	}
+
+	if _, err := c.file.Write(append(line, '\n')); err != nil {
+		log.Printf("unable to write cache file %s: %v", c.path, err)
+		return
+	}
+
+	c.records++
+
+	if c.records > 2*c.memory.Len()+fileCacheCompactSlack {
+		if err := c.compact(); err != nil {
+			log.Printf("unable to compact cache file %s: %v", c.path, err)
+		}
+	}
+}
+
+// compact atomically replaces the log with one record per live entry,
+// oldest first so that reloading it restores the LRU order.
+func (c *FileCache) compact() error {
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".cache-*.tmp")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	records := 0
+
+	c.memory.each(func(key string, entry CacheEntry) {
+		if err == nil {
+			err = encoder.Encode(fileCacheRecord{Key: key, Entry: &entry})
+			records++
+		}
+	})
+
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return err
+	}
+
+	if c.file != nil {
+		c.file.Close()
+	}
+
+	c.file, err = os.OpenFile(c.path, os.O_WRONLY|os.O_APPEND, 0o644)
+
+	if err != nil {
+		return err
+	}
+
+	c.records = records
+
+	return nil
+}
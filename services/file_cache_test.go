@@ -0,0 +1,199 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openFileCache(t *testing.T, path string, maxEntries int) *FileCache {
+	t.Helper()
+
+	c, err := NewFileCache(path, maxEntries)
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cache file: %v", err)
+	}
+
+	return bytes.Count(data, []byte("\n"))
+}
+
+func TestFileCache_PersistsAcrossRestarts(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+	expires := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	c := openFileCache(t, path, 10)
+	c.Set("a", CacheEntry{Body: json.RawMessage(`{"a":1}`), Expires: expires, ETag: `"v1"`, Value: "not persisted"})
+	c.Set("b", CacheEntry{Body: json.RawMessage(`{"b":2}`)})
+	c.Remove("b")
+	c.Close()
+
+	c = openFileCache(t, path, 10)
+
+	got, ok := c.Get("a")
+	if !ok {
+		t.Fatal("expected a to survive a restart")
+	}
+	if string(got.Body) != `{"a":1}` || !got.Expires.Equal(expires) || got.ETag != `"v1"` {
+		t.Fatalf("unexpected entry: %#v", got)
+	}
+	if got.Value != nil {
+		t.Fatalf("expected decoded value not to be persisted, got %#v", got.Value)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("expected removal of b to be persisted")
+	}
+}
+
+func TestFileCache_SkipsCorruptRecords(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+
+	content := strings.Join([]string{
+		`{"key":"a","entry":{"body":{"a":1}}}`,
+		`not json at all`,
+		`{"entry":{"body":{"missing":"key"}}}`,
+		`{"key":"b","entry":{"body":{"b":2}}}`,
+		`{"key":"c","entry":{"bo`, // half-written by a crash
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := openFileCache(t, path, 10)
+
+	if c.Len() != 2 {
+		t.Fatalf("expected two valid entries, got %d", c.Len())
+	}
+	if _, ok := c.Get("c"); ok {
+		t.Fatal("expected truncated record to be skipped")
+	}
+
+	// corrupt lines are dropped so new records are not appended after them
+	if lines := countLines(t, path); lines != 2 {
+		t.Fatalf("expected rewritten file with 2 records, got %d", lines)
+	}
+
+	c.Set("d", CacheEntry{Body: json.RawMessage(`{"d":4}`)})
+	c.Close()
+
+	c = openFileCache(t, path, 10)
+	if c.Len() != 3 {
+		t.Fatalf("expected three entries after reload, got %d", c.Len())
+	}
+}
+
+func TestFileCache_Compacts(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+	c := openFileCache(t, path, 10)
+
+	for i := 0; i < 500; i++ {
+		c.Set("a", CacheEntry{Body: json.RawMessage(`{"a":1}`)})
+	}
+
+	if lines := countLines(t, path); lines > 2*c.Len()+fileCacheCompactSlack {
+		t.Fatalf("expected log to be compacted, got %d lines", lines)
+	}
+
+	c.Close()
+	c = openFileCache(t, path, 10)
+
+	if _, ok := c.Get("a"); !ok || c.Len() != 1 {
+		t.Fatalf("expected single entry after compaction, got len=%d", c.Len())
+	}
+}
+
+func TestFileCache_RestoresLRUOrder(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+
+	c := openFileCache(t, path, 3)
+	c.Set("a", CacheEntry{})
+	c.Set("b", CacheEntry{})
+	c.Set("c", CacheEntry{})
+	c.Close()
+
+	// reopening with a smaller bound keeps the most recently set entries
+	c = openFileCache(t, path, 2)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected least recently used entry to be evicted")
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Fatal("expected b to be kept")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatal("expected c to be kept")
+	}
+}
+
+func TestNwsAPI_GetForecast_WarmStartFromFileCache(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		switch req.URL.Path {
+		case "/points/1,2":
+			body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		default:
+			body := `{"properties":{"periods":[{"shortForecast":"Sunny","temperature":90}]}}`
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}
+	})
+
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+
+	cache := openFileCache(t, path, 10)
+	if _, err := NewClient(WithTransport(transport), WithCache(cache)).GetForecast(context.Background(), testPoint, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cache.Close()
+
+	if calls != 2 {
+		t.Fatalf("expected cold start to call upstream twice, got %d", calls)
+	}
+
+	cache = openFileCache(t, path, 10)
+	f, err := NewClient(WithTransport(transport), WithCache(cache)).GetForecast(context.Background(), testPoint, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected warm start to be served from disk, got %d upstream calls", calls)
+	}
+	if f.ForecastDaily != "Sunny" || f.Temperature != 90 {
+		t.Fatalf("unexpected forecast: %#v", f)
+	}
+
+	// The point decoded from disk is kept so the next hit skips decoding
+	if entry, _ := cache.Get("https://api.weather.gov/points/1,2"); entry.Value == nil {
+		t.Fatal("expected the decoded point to be stored back")
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheControl holds the Cache-Control directives we act on.
+type cacheControl struct {
+	noStore bool
+	noCache bool
+	maxAge  time.Duration
+	hasAge  bool
+}
+
+func parseCacheControl(value string) cacheControl {
+	var cc cacheControl
+	var sMaxAge time.Duration
+	var hasSMaxAge bool
+
+	for _, directive := range strings.Split(value, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		arg = strings.Trim(strings.TrimSpace(arg), `"`)
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "no-store":
+			cc.noStore = true
+		case "no-cache":
+			cc.noCache = true
+		case "max-age":
+			if seconds, err := strconv.Atoi(arg); err == nil && seconds >= 0 {
+				cc.maxAge = time.Duration(seconds) * time.Second
+				cc.hasAge = true
+			}
+		case "s-maxage":
+			if seconds, err := strconv.Atoi(arg); err == nil && seconds >= 0 {
+				sMaxAge = time.Duration(seconds) * time.Second
+				hasSMaxAge = true
+			}
+		}
+	}
+
+	// We are a shared cache, so s-maxage wins over max-age
+	if hasSMaxAge {
+		cc.maxAge = sMaxAge
+		cc.hasAge = true
+	}
+
+	return cc
+}
+
+// cacheable reports whether a response with header may be stored at all.
+func cacheable(header http.Header) bool {
+	return !parseCacheControl(header.Get("Cache-Control")).noStore
+}
+
+// freshnessLifetime derives how long a response stays fresh from its
+// Cache-Control, Age, Expires and Date headers (RFC 9111 section 4.2.1).
+// When upstream says nothing about freshness, fallback is used instead.
+func freshnessLifetime(header http.Header, now time.Time, fallback time.Duration) time.Duration {
+	cc := parseCacheControl(header.Get("Cache-Control"))
+
+	if cc.noStore || cc.noCache {
+		return 0
+	}
+
+	if cc.hasAge {
+		lifetime := cc.maxAge
+
+		if age, err := strconv.Atoi(header.Get("Age")); err == nil && age > 0 {
+			lifetime -= time.Duration(age) * time.Second
+		}
+
+		return max(lifetime, 0)
+	}
+
+	if value := header.Get("Expires"); value != "" {
+		expires, err := http.ParseTime(value)
+
+		// An invalid Expires, such as "0", means already expired
+		if err != nil {
+			return 0
+		}
+
+		date := now
+		if parsed, err := http.ParseTime(header.Get("Date")); err == nil {
+			date = parsed
+		}
+
+		return max(expires.Sub(date), 0)
+	}
+
+	return fallback
+}
+
+// conditionalHeaders returns the validators to send when revalidating entry.
+func conditionalHeaders(entry CacheEntry) http.Header {
+	header := make(http.Header)
+
+	if entry.ETag != "" {
+		header.Set("If-None-Match", entry.ETag)
+	}
+
+	if entry.LastModified != "" {
+		header.Set("If-Modified-Since", entry.LastModified)
+	}
+
+	return header
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFreshnessLifetime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fallback := 5 * time.Minute
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   time.Duration
+	}{
+		{"no headers uses fallback", nil, fallback},
+		{"max-age", map[string]string{"Cache-Control": "public, max-age=600"}, 10 * time.Minute},
+		{"max-age minus age", map[string]string{"Cache-Control": "max-age=600", "Age": "120"}, 8 * time.Minute},
+		{"age beyond max-age", map[string]string{"Cache-Control": "max-age=60", "Age": "120"}, 0},
+		{"s-maxage wins", map[string]string{"Cache-Control": "max-age=600, s-maxage=60"}, time.Minute},
+		{"no-cache", map[string]string{"Cache-Control": "no-cache, max-age=600"}, 0},
+		{"no-store", map[string]string{"Cache-Control": "no-store"}, 0},
+		{"max-age beats expires", map[string]string{"Cache-Control": "max-age=60", "Expires": now.Add(time.Hour).Format(http.TimeFormat)}, time.Minute},
+		{"expires relative to date", map[string]string{
+			"Date":    now.Add(-time.Minute).Format(http.TimeFormat),
+			"Expires": now.Add(time.Hour).Format(http.TimeFormat),
+		}, 61 * time.Minute},
+		{"expires relative to now", map[string]string{"Expires": now.Add(time.Hour).Format(http.TimeFormat)}, time.Hour},
+		{"expires in the past", map[string]string{"Expires": now.Add(-time.Hour).Format(http.TimeFormat)}, 0},
+		{"invalid expires", map[string]string{"Expires": "0"}, 0},
+		{"unknown directives use fallback", map[string]string{"Cache-Control": "public"}, fallback},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			header := make(http.Header)
+			for k, v := range tc.header {
+				header.Set(k, v)
+			}
+
+			if got := freshnessLifetime(header, now, fallback); got != tc.want {
+				t.Fatalf("got %v want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	t.Parallel()
+
+	header := make(http.Header)
+	if !cacheable(header) {
+		t.Fatal("expected response without Cache-Control to be cacheable")
+	}
+
+	header.Set("Cache-Control", "private, no-store")
+	if cacheable(header) {
+		t.Fatal("expected no-store response not to be cacheable")
+	}
+}
+
+func TestConditionalHeaders(t *testing.T) {
+	t.Parallel()
+
+	header := conditionalHeaders(CacheEntry{ETag: `"abc"`, LastModified: "Wed, 01 Jan 2025 12:00:00 GMT"})
+
+	if got := header.Get("If-None-Match"); got != `"abc"` {
+		t.Fatalf("If-None-Match: got %q", got)
+	}
+	if got := header.Get("If-Modified-Since"); got != "Wed, 01 Jan 2025 12:00:00 GMT" {
+		t.Fatalf("If-Modified-Since: got %q", got)
+	}
+
+	if header := conditionalHeaders(CacheEntry{}); len(header) != 0 {
+		t.Fatalf("expected no validators, got %v", header)
+	}
+}
@@ -1,58 +1,234 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
 
 	"github.com/rmccullagh/weather-api/models"
+	"golang.org/x/sync/singleflight"
 )
 
-const baseURL = "https://api.weather.gov"
+// DefaultBaseURL is the National Weather Service API.
+const DefaultBaseURL = "https://api.weather.gov"
 
-type nwsAPI struct{}
+// DefaultCacheMaxEntries is how many responses the cache holds unless
+// WithCacheSize says otherwise.
+const DefaultCacheMaxEntries = 1024
+
+const (
+	defaultPointsTTL      = 24 * time.Hour
+	defaultForecastTTL    = 15 * time.Minute
+	defaultAlertsTTL      = time.Minute
+	defaultObservationTTL = 5 * time.Minute
+	defaultRequestTimeout = 10 * time.Second
+	defaultTimeout        = 20 * time.Second
+	defaultRateLimit      = 5
+	defaultRateBurst      = 10
+)
+
+const (
+	// maxStations is how many of the nearest stations are tried for an
+	// observation.
+	maxStations = 3
+	// maxObservationAge is how old an observation may be to count as
+	// current conditions.
+	maxObservationAge = 2 * time.Hour
+)
+
+// DefaultUserAgent identifies this application to NWS. Deployments should
+// set their own contact details with WithUserAgent.
+const DefaultUserAgent = "(github.com/rmccullagh/weather-api, weather-api)"
+
+type nwsAPI struct {
+	httpClient  *http.Client
+	transport   http.RoundTripper
+	baseURL     string
+	cache       Cache
+	now         func() time.Time
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	flights     flightGroup
+	pointsTTL   time.Duration
+	forecastTTL time.Duration
+	alertsTTL   time.Duration
+	// observationTTL is not configurable; stations report hourly at most
+	observationTTL time.Duration
+
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+	// alertsStaleIfError replaces staleIfError for alerts, since stale
+	// alerts miss any warning issued after them
+	alertsStaleIfError time.Duration
+
+	requestTimeout time.Duration
+	timeout        time.Duration
+	retry          RetryPolicy
+	breakerPolicy  BreakerPolicy
+	breaker        *breaker
+	limiter        *rateLimiter
+	allowedOrigins []origin
+	userAgent      string
+	units          models.UnitSystem
+}
+
+// flightGroup shares one upstream request between concurrent lookups of
+// the same endpoint, as *singleflight.Group does.
+type flightGroup interface {
+	DoChan(key string, fn func() (any, error)) <-chan singleflight.Result
+}
+
+// cacheStatus describes a response that was served from the cache after
+// it expired, and how long ago it was fetched or last revalidated.
+type cacheStatus struct {
+	stale bool
+	age   time.Duration
+}
+
+// mark records on a response built from the cached one that it was stale.
+func (s cacheStatus) mark(f *models.Freshness) {
+	if s.stale {
+		f.MarkStale(s.age)
+	}
+}
 
 type pointResponse struct {
 	Properties struct {
-		Forecast string `json:"forecast"`
+		Forecast            string `json:"forecast"`
+		ForecastHourly      string `json:"forecastHourly"`
+		ObservationStations string `json:"observationStations"`
+		TimeZone            string `json:"timeZone"`
 	} `json:"properties"`
 }
 
+// link returns the link named name, as NWS names it in the metadata.
+func (p *pointResponse) link(name string) string {
+	switch name {
+	case "forecast":
+		return p.Properties.Forecast
+	case "forecastHourly":
+		return p.Properties.ForecastHourly
+	case "observationStations":
+		return p.Properties.ObservationStations
+	}
+
+	return ""
+}
+
+// location returns the point's time zone, or nil when NWS sent none or one
+// we do not know.
+func (p *pointResponse) location() *time.Location {
+	if p.Properties.TimeZone == "" {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(p.Properties.TimeZone)
+
+	if err != nil {
+		log.Printf("unknown time zone %q: %v", p.Properties.TimeZone, err)
+		return nil
+	}
+
+	return loc
+}
+
+// errorResponse is the problem document NWS sends with most errors.
 type errorResponse struct {
-	Detail string `json:"detail"`
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Detail        string `json:"detail"`
+	CorrelationID string `json:"correlationId"`
+}
+
+// upstreamResponse is a response read in full from upstream.
+type upstreamResponse struct {
+	url        string
+	statusCode int
+	header     http.Header
+	body       []byte
 }
 
-func doHTTPGet[T any](endpoint string) (*T, error) {
-	resp, err := http.DefaultClient.Get(endpoint)
+// err turns any status other than 200 or 304 into an UpstreamStatusError.
+func (r *upstreamResponse) err() error {
+	if r.statusCode == http.StatusOK || r.statusCode == http.StatusNotModified {
+		return nil
+	}
+
+	statusErr := &UpstreamStatusError{URL: r.url, StatusCode: r.statusCode}
+
+	// try to get the error
+	var errorResponse errorResponse
+
+	if json.Unmarshal(r.body, &errorResponse) == nil {
+		statusErr.Type = errorResponse.Type
+		statusErr.Title = errorResponse.Title
+		statusErr.Detail = errorResponse.Detail
+		statusErr.CorrelationID = errorResponse.CorrelationID
+	}
+
+	return statusErr
+}
+
+// roundTrip performs a single GET against endpoint with the extra header
+// set and reads the whole response, whatever its status.
+func roundTrip(ctx context.Context, client *http.Client, endpoint string, header http.Header) (*upstreamResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		var linkErr *LinkNotAllowedError
+		if errors.As(err, &linkErr) {
+			return nil, linkErr
+		}
+
+		return nil, &NetworkError{URL: endpoint, Err: err}
+	}
+
+	defer resp.Body.Close()
+
 	body, err := io.ReadAll(resp.Body)
 
 	if err != nil {
-		return nil, err
+		return nil, &NetworkError{URL: endpoint, Err: err}
 	}
 
-	if resp.StatusCode != 200 {
-		// try to get the error
-		var errorResponse errorResponse
+	return &upstreamResponse{url: endpoint, statusCode: resp.StatusCode, header: resp.Header, body: body}, nil
+}
 
-		err = json.Unmarshal(body, &errorResponse)
+// checked returns the outcome of roundTrip, turning any status other than
+// 200 or 304 into an error.
+func checked(resp *upstreamResponse, err error) (*upstreamResponse, error) {
+	if err != nil {
+		return nil, err
+	}
 
-		if err == nil {
-			return nil, errors.New(errorResponse.Detail)
-		} else {
-			return nil, errors.New("non 200 response from upstream")
-		}
+	if err := resp.err(); err != nil {
+		return nil, err
 	}
 
+	return resp, nil
+}
+
+func decode[T any](body []byte) (*T, error) {
 	var model T
 
-	err = json.Unmarshal(body, &model)
+	err := json.Unmarshal(body, &model)
 
 	if err != nil {
 		return nil, err
@@ -61,21 +237,527 @@ func doHTTPGet[T any](endpoint string) (*T, error) {
 	return &model, nil
 }
 
+// cachedValue returns the decoded form of entry, decoding its body when
+// the entry was loaded from a persistent cache. The decoded form is stored
+// back under endpoint so later hits skip decoding.
+func cachedValue[T any](n *nwsAPI, endpoint string, entry CacheEntry) (*T, bool) {
+	if model, ok := entry.Value.(*T); ok {
+		return model, true
+	}
+
+	model, err := decode[T](entry.Body)
+
+	if err != nil {
+		return nil, false
+	}
+
+	if entry.Value == nil {
+		entry.Value = model
+		n.cache.Set(endpoint, entry)
+	}
+
+	return model, true
+}
+
+// cachePolicy says how long a cached response is fresh when upstream does
+// not send caching headers, and for how long past its expiry it may still
+// be served.
+type cachePolicy struct {
+	ttl                  time.Duration
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+}
+
+// policy returns the client's stale windows for responses fresh for ttl.
+func (n *nwsAPI) policy(ttl time.Duration) cachePolicy {
+	return cachePolicy{ttl: ttl, staleWhileRevalidate: n.staleWhileRevalidate, staleIfError: n.staleIfError}
+}
+
+// cachedGet serves endpoint from the client's cache while it is fresh.
+// Concurrent lookups of the same endpoint that miss the cache share a
+// single upstream request and all receive its result or error.
+//
+// An expired entry is served as is for up to policy.staleWhileRevalidate
+// past its expiry while a background refresh runs, and for up to
+// policy.staleIfError when upstream fails. The returned status says
+// whether that happened.
+//
+// The upstream request is shared, so it is not cancelled when ctx is; it
+// runs with its own timeout and ctx only bounds how long we wait.
+func cachedGet[T any](ctx context.Context, n *nwsAPI, endpoint string, policy cachePolicy) (*T, cacheStatus, error) {
+	entry, found := n.cache.Get(endpoint)
+	var model *T
+
+	if found {
+		model, found = cachedValue[T](n, endpoint, entry)
+	}
+
+	now := n.now()
+
+	if found && entry.fresh(now) {
+		n.hits.Add(1)
+		return model, cacheStatus{}, nil
+	}
+
+	stale := cacheStatus{stale: true, age: now.Sub(entry.Stored)}
+
+	if found && entry.usableUntil(now, policy.staleWhileRevalidate) {
+		n.hits.Add(1)
+
+		n.flights.DoChan(endpoint, func() (any, error) {
+			return n.refresh(context.Background(), func(ctx context.Context) (any, error) {
+				return refresh[T](ctx, n, endpoint, policy.ttl)
+			})
+		})
+
+		return model, stale, nil
+	}
+
+	var value any
+	var err error
+
+	results := n.flights.DoChan(endpoint, func() (any, error) {
+		return n.refresh(ctx, func(ctx context.Context) (any, error) {
+			return refresh[T](ctx, n, endpoint, policy.ttl)
+		})
+	})
+
+	select {
+	case result := <-results:
+		value, err = result.Val, result.Err
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		if found && entry.usableUntil(now, policy.staleIfError) {
+			log.Printf("serving stale %s (age %s) after upstream error: %v", endpoint, stale.age.Round(time.Second), err)
+			return model, stale, nil
+		}
+
+		return nil, cacheStatus{}, err
+	}
+
+	return value.(*T), cacheStatus{}, nil
+}
+
+// refresh fetches endpoint from upstream and stores the result. A cached
+// entry is revalidated with If-None-Match and If-Modified-Since, and a 304
+// from upstream counts as a cache hit. Freshness comes from the upstream
+// caching headers; ttl is only used when upstream does not send any.
+func refresh[T any](ctx context.Context, n *nwsAPI, endpoint string, ttl time.Duration) (*T, error) {
+	entry, found := n.cache.Get(endpoint)
+	var cached *T
+
+	if found {
+		cached, found = cachedValue[T](n, endpoint, entry)
+	}
+
+	// A caller that missed the cache just as the previous shared request
+	// stored a fresh entry starts a new one; serve it that entry instead.
+	if found && entry.fresh(n.now()) {
+		n.hits.Add(1)
+		return cached, nil
+	}
+
+	var header http.Header
+
+	if found {
+		header = conditionalHeaders(entry)
+	}
+
+	resp, err := n.fetch(ctx, endpoint, header)
+
+	if err != nil {
+		n.misses.Add(1)
+		return nil, err
+	}
+
+	now := n.now()
+
+	if resp.statusCode == http.StatusNotModified {
+		if !found {
+			return nil, &UpstreamStatusError{URL: endpoint, StatusCode: resp.statusCode}
+		}
+
+		n.hits.Add(1)
+
+		entry.Value = cached
+		entry.Stored = now
+		entry.Expires = now.Add(freshnessLifetime(resp.header, now, ttl))
+		if etag := resp.header.Get("ETag"); etag != "" {
+			entry.ETag = etag
+		}
+		if lastModified := resp.header.Get("Last-Modified"); lastModified != "" {
+			entry.LastModified = lastModified
+		}
+		n.cache.Set(endpoint, entry)
+
+		return cached, nil
+	}
+
+	n.misses.Add(1)
+
+	model, err := decode[T](resp.body)
+
+	if err != nil {
+		return nil, &DecodeError{URL: endpoint, Err: err}
+	}
+
+	if !cacheable(resp.header) {
+		n.cache.Remove(endpoint)
+		return model, nil
+	}
+
+	n.cache.Set(endpoint, CacheEntry{
+		Body:         resp.body,
+		Stored:       now,
+		Expires:      now.Add(freshnessLifetime(resp.header, now, ttl)),
+		ETag:         resp.header.Get("ETag"),
+		LastModified: resp.header.Get("Last-Modified"),
+		Value:        model,
+	})
+
+	return model, nil
+}
+
+// refresh runs fn for a shared upstream request. It keeps the values of
+// ctx but not its cancellation, since other callers may be waiting on the
+// same request, and bounds it with the overall timeout instead.
+func (n *nwsAPI) refresh(ctx context.Context, fn func(context.Context) (any, error)) (any, error) {
+	ctx, cancel := n.withTimeout(context.WithoutCancel(ctx))
+	defer cancel()
+
+	return fn(ctx)
+}
+
+// point looks up the /points metadata for coordinate, which links to its
+// forecasts.
+//
 // See https://www.weather.gov/documentation/services-web-api
-func (n *nwsAPI) GetForecast(latitude, longitude string) (*models.Forecast, error) {
-	point, err := doHTTPGet[pointResponse](baseURL + fmt.Sprintf("/points/%s,%s", latitude, longitude))
+func (n *nwsAPI) point(ctx context.Context, coordinate models.Coordinate) (*pointResponse, error) {
+	point, _, err := cachedGet[pointResponse](ctx, n, n.baseURL+"/points/"+coordinate.String(), n.policy(n.pointsTTL))
+
+	return point, err
+}
+
+// follow fetches a forecast linked from /points metadata. The returned
+// status says whether it was stale.
+func (n *nwsAPI) follow(ctx context.Context, link string) (*models.ForecastResponse, cacheStatus, error) {
+	if err := n.checkLink(link); err != nil {
+		return nil, cacheStatus{}, err
+	}
+
+	return cachedGet[models.ForecastResponse](ctx, n, link, n.policy(n.forecastTTL))
+}
+
+// pointLink returns the link named name from the /points metadata for
+// coordinate. A missing link is reported as unavailable data rather than
+// a link that is not allowed.
+func (n *nwsAPI) pointLink(coordinate models.Coordinate, name, link string) (string, error) {
+	if link == "" {
+		return "", &DataUnavailableError{URL: n.baseURL + "/points/" + coordinate.String(), Err: fmt.Errorf("%w: %s", ErrMissingLink, name)}
+	}
+
+	return link, nil
+}
+
+// unitsOr returns units, or the client's default when it is empty.
+func (n *nwsAPI) unitsOr(units models.UnitSystem) models.UnitSystem {
+	if units == "" {
+		return n.units
+	}
+
+	return units
+}
+
+// forecastLink asks NWS for a forecast linked from /points metadata in
+// units. NWS gives forecasts in US units unless asked for SI ones.
+func forecastLink(link string, units models.UnitSystem) string {
+	if units != models.SI {
+		return link
+	}
+
+	u, err := url.Parse(link)
+
+	if err != nil {
+		return link
+	}
+
+	q := u.Query()
+	q.Set("units", "si")
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// linkedForecast is a forecast fetched through the /points metadata of a
+// location.
+type linkedForecast struct {
+	point    *pointResponse
+	link     string
+	forecast *models.ForecastResponse
+	status   cacheStatus
+}
+
+// forecastFor looks up the /points metadata for coordinate and fetches the
+// forecast it links to as name, in units.
+func (n *nwsAPI) forecastFor(ctx context.Context, coordinate models.Coordinate, name string, units models.UnitSystem) (*linkedForecast, error) {
+	point, err := n.point(ctx, coordinate)
 
 	if err != nil {
 		return nil, err
 	}
 
-	forecast, err := doHTTPGet[models.ForecastResponse](point.Properties.Forecast)
+	return n.linked(ctx, coordinate, point, name, units)
+}
+
+// linked fetches the forecast point links to as name, in units.
+func (n *nwsAPI) linked(ctx context.Context, coordinate models.Coordinate, point *pointResponse, name string, units models.UnitSystem) (*linkedForecast, error) {
+	link, err := n.pointLink(coordinate, name, point.link(name))
 
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO: cache the response in memory to reduce latency (key: URL, value: JSON blob)
+	link = forecastLink(link, units)
+	forecast, status, err := n.follow(ctx, link)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &linkedForecast{point: point, link: link, forecast: forecast, status: status}, nil
+}
+
+// unavailable reports forecast data from endpoint that could not be mapped,
+// dropping it from the cache so it is not served again until it expires.
+func (n *nwsAPI) unavailable(endpoint string, err error) error {
+	n.cache.Remove(endpoint)
+
+	return &DataUnavailableError{URL: endpoint, Err: err}
+}
+
+// withTimeout bounds ctx by the overall timeout, when there is one.
+func (n *nwsAPI) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if n.timeout > 0 {
+		return context.WithTimeout(ctx, n.timeout)
+	}
+
+	return ctx, func() {}
+}
+
+func (n *nwsAPI) GetForecast(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.Forecast, error) {
+	ctx, cancel := n.withTimeout(ctx)
+	defer cancel()
+
+	units = n.unitsOr(units)
+	f, err := n.forecastFor(ctx, coordinate, "forecast", units)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := models.NewForecastFromUpstream(f.forecast, n.now(), f.point.location())
+
+	if err != nil {
+		return nil, n.unavailable(f.link, err)
+	}
+
+	result.ConvertTo(units)
+	f.status.mark(&result.Freshness)
+
+	return result, nil
+}
+
+func (n *nwsAPI) GetForecastPeriods(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.ForecastPeriods, error) {
+	ctx, cancel := n.withTimeout(ctx)
+	defer cancel()
+
+	units = n.unitsOr(units)
+	f, err := n.forecastFor(ctx, coordinate, "forecast", units)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := models.NewForecastPeriodsFromUpstream(f.forecast)
+
+	if err != nil {
+		return nil, n.unavailable(f.link, err)
+	}
+
+	result.ConvertTo(units)
+	f.status.mark(&result.Freshness)
+
+	return result, nil
+}
+
+func (n *nwsAPI) GetHourlyForecast(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem, query models.HourlyQuery) (*models.HourlyForecast, error) {
+	ctx, cancel := n.withTimeout(ctx)
+	defer cancel()
+
+	units = n.unitsOr(units)
+	f, err := n.forecastFor(ctx, coordinate, "forecastHourly", units)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Start.IsZero() {
+		query.Start = n.now()
+	}
+
+	result, err := models.NewHourlyForecastFromUpstream(f.forecast, query)
+
+	if err != nil {
+		return nil, n.unavailable(f.link, err)
+	}
 
-	return models.NewForecastFromUpstream(forecast), nil
+	result.ConvertTo(units)
+	f.status.mark(&result.Freshness)
+
+	return result, nil
+}
+
+// GetDailyForecast folds the forecast into one summary per local day,
+// refining it with the hourly forecast. The hourly forecast is optional:
+// when it cannot be fetched the summary is built from the periods alone.
+func (n *nwsAPI) GetDailyForecast(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.DailyForecast, error) {
+	ctx, cancel := n.withTimeout(ctx)
+	defer cancel()
+
+	units = n.unitsOr(units)
+	f, err := n.forecastFor(ctx, coordinate, "forecast", units)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var hourly *models.ForecastResponse
+	h, err := n.linked(ctx, coordinate, f.point, "forecastHourly", units)
+
+	if err != nil {
+		log.Printf("daily forecast for %s without hourly data: %v", coordinate, err)
+	} else {
+		hourly = h.forecast
+	}
+
+	result, err := models.NewDailyForecastFromUpstream(f.forecast, hourly, f.point.location())
+
+	if err != nil {
+		return nil, n.unavailable(f.link, err)
+	}
+
+	result.ConvertTo(units)
+	f.status.mark(&result.Freshness)
+
+	return result, nil
+}
+
+// GetAlerts looks up the alerts in effect at coordinate. All active alerts
+// for the point are cached together and filtered by query afterwards.
+// Expired alerts are never served while revalidating, and only for up to
+// alertsStaleIfError when NWS fails.
+func (n *nwsAPI) GetAlerts(ctx context.Context, coordinate models.Coordinate, query models.AlertQuery) (*models.Alerts, error) {
+	ctx, cancel := n.withTimeout(ctx)
+	defer cancel()
+
+	alerts, status, err := cachedGet[models.AlertsResponse](ctx, n, n.baseURL+"/alerts/active?point="+coordinate.String(), cachePolicy{ttl: n.alertsTTL, staleIfError: n.alertsStaleIfError})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.NewAlertsFromUpstream(alerts, query, n.now())
+	status.mark(&result.Freshness)
+
+	return result, nil
+}
+
+// GetConditions returns the latest observation from the station nearest
+// to coordinate. Stations that fail, have not reported for
+// maxObservationAge or report no temperature are skipped in favour of the
+// next nearest, up to maxStations of them.
+func (n *nwsAPI) GetConditions(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.Conditions, error) {
+	ctx, cancel := n.withTimeout(ctx)
+	defer cancel()
+
+	point, err := n.point(ctx, coordinate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := n.pointLink(coordinate, "observationStations", point.link("observationStations"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := n.checkLink(link); err != nil {
+		return nil, err
+	}
+
+	stations, _, err := cachedGet[models.StationsResponse](ctx, n, link, n.policy(n.pointsTTL))
+
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := models.NearestStations(stations, coordinate, maxStations)
+	failed := 0
+	var lastErr error
+
+	for _, station := range candidates {
+		observation, status, err := n.observation(ctx, station)
+
+		if err != nil {
+			// Only our own deadline stops us trying the next station
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			log.Printf("skipping station %s: %v", station.ID, err)
+			failed++
+			lastErr = err
+			continue
+		}
+
+		if !observation.Usable(n.now(), maxObservationAge) {
+			log.Printf("skipping station %s: no recent observation with a temperature", station.ID)
+			continue
+		}
+
+		result := models.NewConditionsFromUpstream(station, observation)
+		result.ConvertTo(n.unitsOr(units))
+		status.mark(&result.Freshness)
+
+		return result, nil
+	}
+
+	if failed > 0 && failed == len(candidates) {
+		return nil, lastErr
+	}
+
+	return nil, &DataUnavailableError{URL: link, Err: models.ErrNoObservation}
+}
+
+func (n *nwsAPI) observation(ctx context.Context, station models.Station) (*models.ObservationResponse, cacheStatus, error) {
+	link := station.URL + "/observations/latest"
+
+	if err := n.checkLink(link); err != nil {
+		return nil, cacheStatus{}, err
+	}
+
+	return cachedGet[models.ObservationResponse](ctx, n, link, n.policy(n.observationTTL))
+}
+
+// CacheStats reports hit and miss counts for the client's response cache.
+func (n *nwsAPI) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:    n.hits.Load(),
+		Misses:  n.misses.Load(),
+		Entries: n.cache.Len(),
+	}
 }
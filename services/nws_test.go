@@ -1,17 +1,29 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/rmccullagh/weather-api/models"
+	"golang.org/x/sync/singleflight"
 )
 
-func TestDoHTTPGet_Success(t *testing.T) {
+// testPoint is requested from NWS as /points/1,2.
+var testPoint = models.Coordinate{Latitude: 1, Longitude: 2}
+
+func TestCachedGet_Success(t *testing.T) {
+	t.Parallel()
+
 	var ts *httptest.Server
 	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(200)
@@ -19,7 +31,9 @@ func TestDoHTTPGet_Success(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	got, err := doHTTPGet[pointResponse](ts.URL)
+	c := NewClient().(*nwsAPI)
+
+	got, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -28,27 +42,35 @@ func TestDoHTTPGet_Success(t *testing.T) {
 	}
 }
 
-func TestDoHTTPGet_Non200_WithErrorResponse(t *testing.T) {
+func TestCachedGet_Non200_WithErrorResponse(t *testing.T) {
+	t.Parallel()
+
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(400)
 		io.WriteString(w, `{"detail":"bad request happened"}`)
 	}))
 	defer ts.Close()
 
-	_, err := doHTTPGet[pointResponse](ts.URL)
+	c := NewClient().(*nwsAPI)
+
+	_, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
 	if err == nil || !strings.Contains(err.Error(), "bad request happened") {
 		t.Fatalf("expected error containing detail, got: %v", err)
 	}
 }
 
-func TestDoHTTPGet_Non200_NonJSON(t *testing.T) {
+func TestCachedGet_Non200_NonJSON(t *testing.T) {
+	t.Parallel()
+
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(500)
 		io.WriteString(w, `internal server error`)
 	}))
 	defer ts.Close()
 
-	_, err := doHTTPGet[pointResponse](ts.URL)
+	c := NewClient(WithRetryPolicy(RetryPolicy{})).(*nwsAPI)
+
+	_, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
 	if err == nil || !strings.Contains(err.Error(), "non 200 response from upstream") {
 		t.Fatalf("expected non-200 non-json error, got: %v", err)
 	}
@@ -60,24 +82,22 @@ func (errRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
 	return nil, errors.New("network fail")
 }
 
-func TestDoHTTPGet_NetworkError(t *testing.T) {
-	orig := http.DefaultTransport
-	http.DefaultTransport = errRoundTripper{}
-	defer func() { http.DefaultTransport = orig }()
+func TestCachedGet_NetworkError(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient(WithTransport(errRoundTripper{}), WithRetryPolicy(RetryPolicy{})).(*nwsAPI)
 
-	_, err := doHTTPGet[pointResponse]("http://example.invalid")
+	_, _, err := cachedGet[pointResponse](context.Background(), c, "http://example.invalid", c.policy(c.pointsTTL))
 	if err == nil || !strings.Contains(err.Error(), "network fail") {
 		t.Fatalf("expected network error, got: %v", err)
 	}
 }
 
 func TestNwsAPI_GetForecast_Success(t *testing.T) {
-	origTransport := http.DefaultTransport
-	// restore at end
-	defer func() { http.DefaultTransport = origTransport }()
+	t.Parallel()
 
 	// Create a transport that responds to the two expected paths.
-	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		switch req.URL.Path {
 		case "/points/1,2":
 			body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
@@ -112,8 +132,8 @@ func TestNwsAPI_GetForecast_Success(t *testing.T) {
 		}
 	})
 
-	c := NewClient()
-	f, err := c.GetForecast("1", "2")
+	c := NewClient(WithTransport(transport))
+	f, err := c.GetForecast(context.Background(), testPoint, "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -126,10 +146,9 @@ func TestNwsAPI_GetForecast_Success(t *testing.T) {
 }
 
 func TestNwsAPI_GetForecast_PointNetworkError(t *testing.T) {
-	orig := http.DefaultTransport
-	defer func() { http.DefaultTransport = orig }()
+	t.Parallel()
 
-	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		if strings.HasPrefix(req.URL.Path, "/points/") {
 			return nil, errors.New("network fail")
 		}
@@ -140,18 +159,17 @@ func TestNwsAPI_GetForecast_PointNetworkError(t *testing.T) {
 		}, nil
 	})
 
-	c := NewClient()
-	_, err := c.GetForecast("1", "2")
+	c := NewClient(WithTransport(transport))
+	_, err := c.GetForecast(context.Background(), testPoint, "")
 	if err == nil || !strings.Contains(err.Error(), "network fail") {
 		t.Fatalf("expected network error from points request, got: %v", err)
 	}
 }
 
 func TestNwsAPI_GetForecast_PointNon200_WithDetail(t *testing.T) {
-	orig := http.DefaultTransport
-	defer func() { http.DefaultTransport = orig }()
+	t.Parallel()
 
-	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		if req.URL.Path == "/points/1,2" {
 			return &http.Response{
 				StatusCode: 400,
@@ -166,18 +184,17 @@ func TestNwsAPI_GetForecast_PointNon200_WithDetail(t *testing.T) {
 		}, nil
 	})
 
-	c := NewClient()
-	_, err := c.GetForecast("1", "2")
+	c := NewClient(WithTransport(transport))
+	_, err := c.GetForecast(context.Background(), testPoint, "")
 	if err == nil || !strings.Contains(err.Error(), "bad point") {
 		t.Fatalf("expected detail error from points request, got: %v", err)
 	}
 }
 
 func TestNwsAPI_GetForecast_ForecastNon200_NonJSON(t *testing.T) {
-	orig := http.DefaultTransport
-	defer func() { http.DefaultTransport = orig }()
+	t.Parallel()
 
-	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		switch req.URL.Path {
 		case "/points/1,2":
 			body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
@@ -201,18 +218,17 @@ func TestNwsAPI_GetForecast_ForecastNon200_NonJSON(t *testing.T) {
 		}
 	})
 
-	c := NewClient()
-	_, err := c.GetForecast("1", "2")
+	c := NewClient(WithTransport(transport))
+	_, err := c.GetForecast(context.Background(), testPoint, "")
 	if err == nil || !strings.Contains(err.Error(), "non 200 response from upstream") {
 		t.Fatalf("expected non-200 non-json error from forecast request, got: %v", err)
 	}
 }
 
 func TestNwsAPI_GetForecast_ForecastNon200_WithDetail(t *testing.T) {
-	orig := http.DefaultTransport
-	defer func() { http.DefaultTransport = orig }()
+	t.Parallel()
 
-	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		switch req.URL.Path {
 		case "/points/1,2":
 			body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
@@ -236,14 +252,981 @@ func TestNwsAPI_GetForecast_ForecastNon200_WithDetail(t *testing.T) {
 		}
 	})
 
-	c := NewClient()
-	_, err := c.GetForecast("1", "2")
+	c := NewClient(WithTransport(transport))
+	_, err := c.GetForecast(context.Background(), testPoint, "")
 	if err == nil || !strings.Contains(err.Error(), "not found") {
 		t.Fatalf("expected detail error from forecast request, got: %v", err)
 	}
 }
 
+func TestNwsAPI_GetForecast_CachesUpstreamResponses(t *testing.T) {
+	t.Parallel()
+
+	calls := map[string]int{}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls[req.URL.Path]++
+		switch req.URL.Path {
+		case "/points/1,2":
+			body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		case "/forecast/1":
+			body := `{"properties":{"periods":[{"shortForecast":"Sunny","temperature":90}]}}`
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		default:
+			return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(`{"detail":"not found"}`)), Header: make(http.Header)}, nil
+		}
+	})
+
+	c := NewClient(WithTransport(transport))
+	for i := 0; i < 3; i++ {
+		f, err := c.GetForecast(context.Background(), testPoint, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if f.ForecastDaily != "Sunny" {
+			t.Fatalf("unexpected forecast: %#v", f)
+		}
+	}
+
+	if calls["/points/1,2"] != 1 || calls["/forecast/1"] != 1 {
+		t.Fatalf("expected one upstream call per URL, got %v", calls)
+	}
+
+	stats := c.(*nwsAPI).CacheStats()
+	if stats.Hits != 4 || stats.Misses != 2 {
+		t.Fatalf("unexpected cache stats: %#v", stats)
+	}
+}
+
+func TestNwsAPI_GetForecast_SeparateTTLs(t *testing.T) {
+	t.Parallel()
+
+	calls := map[string]int{}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls[req.URL.Path]++
+		switch req.URL.Path {
+		case "/points/1,2":
+			body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		default:
+			body := `{"properties":{"periods":[{"shortForecast":"Sunny","temperature":90}]}}`
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}
+	})
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewClient(WithTransport(transport), WithCacheTTLs(time.Hour, time.Minute)).(*nwsAPI)
+	c.now = func() time.Time { return now }
+
+	if _, err := c.GetForecast(context.Background(), testPoint, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if _, err := c.GetForecast(context.Background(), testPoint, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls["/points/1,2"] != 1 || calls["/forecast/1"] != 2 {
+		t.Fatalf("expected points cached and forecast refetched, got %v", calls)
+	}
+}
+
+func TestCachedGet_RevalidatesWithETag(t *testing.T) {
+	t.Parallel()
+
+	var requests, notModified int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2025 12:00:00 GMT")
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Wed, 01 Jan 2025 12:00:00 GMT" {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		io.WriteString(w, `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`)
+	}))
+	defer ts.Close()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewClient(WithCacheTTLs(time.Hour, time.Hour)).(*nwsAPI)
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if requests != 1 {
+		t.Fatalf("expected max-age to keep the entry fresh, got %d requests", requests)
+	}
+
+	now = now.Add(61 * time.Second)
+
+	got, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Properties.Forecast != "https://api.weather.gov/forecast/1" {
+		t.Fatalf("unexpected cached value: %#v", got)
+	}
+	if requests != 2 || notModified != 1 {
+		t.Fatalf("expected one conditional request answered with 304, got requests=%d notModified=%d", requests, notModified)
+	}
+
+	stats := c.CacheStats()
+	if stats.Hits != 2 || stats.Misses != 1 {
+		t.Fatalf("expected 304 to count as a hit, got %#v", stats)
+	}
+
+	// the 304 refreshed the entry for another max-age window
+	now = now.Add(30 * time.Second)
+	if _, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected refreshed entry to be served from cache, got %d requests", requests)
+	}
+}
+
+func TestCachedGet_ModifiedResponseReplacesEntry(t *testing.T) {
+	t.Parallel()
+
+	version := 1
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := fmt.Sprintf(`"v%d"`, version)
+		w.Header().Set("Cache-Control", "max-age=0")
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprintf(w, `{"properties":{"forecast":"https://api.weather.gov/forecast/%d"}}`, version)
+	}))
+	defer ts.Close()
+
+	c := NewClient().(*nwsAPI)
+
+	if _, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	version = 2
+
+	got, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Properties.Forecast != "https://api.weather.gov/forecast/2" {
+		t.Fatalf("expected updated value, got %#v", got)
+	}
+
+	entry, _ := c.cache.Get(ts.URL)
+	if entry.ETag != `"v2"` {
+		t.Fatalf("expected stored validator to be updated, got %q", entry.ETag)
+	}
+}
+
+func TestCachedGet_NoStoreIsNotCached(t *testing.T) {
+	t.Parallel()
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "no-store")
+		io.WriteString(w, `{"properties":{"forecast":"x"}}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient().(*nwsAPI)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if requests != 2 {
+		t.Fatalf("expected no-store response to be fetched every time, got %d requests", requests)
+	}
+	if stats := c.CacheStats(); stats.Entries != 0 {
+		t.Fatalf("expected nothing cached, got %#v", stats)
+	}
+}
+
+func TestNwsAPI_GetForecast_CoalescesConcurrentLookups(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	arrived := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		calls[req.URL.Path]++
+		mu.Unlock()
+
+		select {
+		case arrived <- struct{}{}:
+		default:
+		}
+		<-release
+
+		switch req.URL.Path {
+		case "/points/1,2":
+			body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		default:
+			body := `{"properties":{"periods":[{"shortForecast":"Sunny","temperature":90}]}}`
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}
+	})
+
+	const callers = 25
+	c := NewClient(WithTransport(transport))
+
+	results := make([]*models.Forecast, callers)
+	errs := make([]error, callers)
+	start := make(chan struct{})
+
+	var done sync.WaitGroup
+	done.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer done.Done()
+			<-start
+			results[i], errs[i] = c.GetForecast(context.Background(), testPoint, "")
+		}(i)
+	}
+
+	// Callers that arrive after the shared request finished are served
+	// the entry it stored, so none of them may reach upstream again
+	close(start)
+	<-arrived
+	close(release)
+	done.Wait()
+
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("caller %d: unexpected error: %v", i, errs[i])
+		}
+		if !reflect.DeepEqual(results[i], results[0]) {
+			t.Fatalf("caller %d: got %#v want %#v", i, results[i], results[0])
+		}
+	}
+
+	if calls["/points/1,2"] != 1 || calls["/forecast/1"] != 1 {
+		t.Fatalf("expected exactly one upstream call per URL, got %v", calls)
+	}
+}
+
+// joinedGroup calls joined once a caller waits on the shared request for
+// an endpoint, so tests know every caller has joined before releasing it.
+type joinedGroup struct {
+	flightGroup
+	joined func(endpoint string)
+}
+
+func (g joinedGroup) DoChan(key string, fn func() (any, error)) <-chan singleflight.Result {
+	results := g.flightGroup.DoChan(key, fn)
+	g.joined(key)
+
+	return results
+}
+
+func TestCachedGet_CoalescedCallersShareError(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	release := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, `{"detail":"upstream unavailable"}`)
+	}))
+	defer ts.Close()
+
+	const callers = 10
+	// no cache or retries, so only coalescing can keep the request count at one
+	c := NewClient(WithCacheSize(0), WithRetryPolicy(RetryPolicy{})).(*nwsAPI)
+
+	var joined, done sync.WaitGroup
+	joined.Add(callers)
+	c.flights = joinedGroup{c.flights, func(string) { joined.Done() }}
+
+	errs := make([]error, callers)
+
+	done.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer done.Done()
+			_, _, errs[i] = cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
+		}(i)
+	}
+
+	joined.Wait()
+	close(release)
+	done.Wait()
+
+	for i, err := range errs {
+		if err == nil || err.Error() != "upstream unavailable" {
+			t.Fatalf("caller %d: expected shared upstream error, got %v", i, err)
+		}
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected exactly one upstream request, got %d", got)
+	}
+}
+
+func TestCachedGet_StaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+
+	var version atomic.Int32
+	version.Store(1)
+	refreshed := make(chan struct{}, 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		fmt.Fprintf(w, `{"properties":{"forecast":"v%d"}}`, version.Load())
+		if version.Load() == 2 {
+			select {
+			case refreshed <- struct{}{}:
+			default:
+			}
+		}
+	}))
+	defer ts.Close()
+
+	var mu sync.Mutex
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewClient(WithStaleWhileRevalidate(5 * time.Minute)).(*nwsAPI)
+	c.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+
+	if _, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	now = now.Add(2 * time.Minute)
+	mu.Unlock()
+	version.Store(2)
+
+	got, status, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Properties.Forecast != "v1" {
+		t.Fatalf("expected stale value to be served immediately, got %q", got.Properties.Forecast)
+	}
+	if !status.stale || status.age != 2*time.Minute {
+		t.Fatalf("unexpected status: %#v", status)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a background refresh")
+	}
+
+	// wait for the refreshed entry to land in the cache
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got, status, err = cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
+		if err == nil && got.Properties.Forecast == "v2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected refreshed value, got %#v err=%v", got, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if status.stale {
+		t.Fatalf("expected refreshed value to be fresh, got %#v", status)
+	}
+}
+
+func TestCachedGet_StaleIfError(t *testing.T) {
+	t.Parallel()
+
+	var failing atomic.Bool
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			io.WriteString(w, `{"detail":"upstream down"}`)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=60")
+		io.WriteString(w, `{"properties":{"forecast":"v1"}}`)
+	}))
+	defer ts.Close()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewClient(WithStaleIfError(time.Hour), WithBreakerPolicy(BreakerPolicy{})).(*nwsAPI)
+	c.now = func() time.Time { return now }
+
+	if _, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failing.Store(true)
+	now = now.Add(30 * time.Minute)
+
+	got, status, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
+	if err != nil {
+		t.Fatalf("expected stale value instead of error, got %v", err)
+	}
+	if got.Properties.Forecast != "v1" || !status.stale || status.age != 30*time.Minute {
+		t.Fatalf("unexpected result: %#v status=%#v", got, status)
+	}
+
+	// beyond the max-staleness window the error is returned
+	now = now.Add(time.Hour)
+
+	if _, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL)); err == nil || err.Error() != "upstream down" {
+		t.Fatalf("expected upstream error past the stale window, got %v", err)
+	}
+}
+
+func TestNwsAPI_GetForecast_MarksStaleForecast(t *testing.T) {
+	t.Parallel()
+
+	var failing atomic.Bool
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Path {
+		case "/points/1,2":
+			body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		default:
+			if failing.Load() {
+				return nil, errors.New("network fail")
+			}
+			body := `{"properties":{"periods":[{"shortForecast":"Sunny","temperature":90}]}}`
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}
+	})
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewClient(WithTransport(transport), WithCacheTTLs(time.Hour, time.Minute), WithStaleIfError(time.Hour)).(*nwsAPI)
+	c.now = func() time.Time { return now }
+
+	f, err := c.GetForecast(context.Background(), testPoint, "")
+	if err != nil || f.Stale {
+		t.Fatalf("expected fresh forecast, got %#v err=%v", f, err)
+	}
+
+	failing.Store(true)
+	now = now.Add(10 * time.Minute)
+
+	f, err = c.GetForecast(context.Background(), testPoint, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !f.Stale || f.AgeSeconds != 600 || f.ForecastDaily != "Sunny" {
+		t.Fatalf("expected stale forecast aged 600s, got %#v", f)
+	}
+}
+
+// hangingServer never answers until the request is cancelled.
+func hangingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestCachedGet_ContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	ts := hangingServer(t)
+	// the shared request outlives the caller, up to the request timeout
+	c := NewClient(WithRequestTimeout(200 * time.Millisecond)).(*nwsAPI)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := cachedGet[pointResponse](ctx, c, ts.URL, c.policy(c.pointsTTL))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestCachedGet_RequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	ts := hangingServer(t)
+	c := NewClient(WithRequestTimeout(50*time.Millisecond), WithRetryPolicy(RetryPolicy{})).(*nwsAPI)
+
+	start := time.Now()
+	_, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected request timeout to apply, took %v", elapsed)
+	}
+}
+
+func TestCachedGet_CallerCancellationDoesNotCancelSharedRequest(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	var requests atomic.Int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		io.WriteString(w, `{"properties":{"forecast":"x"}}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient().(*nwsAPI)
+
+	joined := make(chan struct{})
+	c.flights = joinedGroup{c.flights, func(string) { joined <- struct{}{} }}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	impatient := make(chan error, 1)
+	go func() {
+		_, _, err := cachedGet[pointResponse](ctx, c, ts.URL, c.policy(c.pointsTTL))
+		impatient <- err
+	}()
+	<-joined
+
+	patient := make(chan error, 1)
+	go func() {
+		_, _, err := cachedGet[pointResponse](context.Background(), c, ts.URL, c.policy(c.pointsTTL))
+		patient <- err
+	}()
+	<-joined
+
+	cancel()
+	if err := <-impatient; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled caller to give up, got %v", err)
+	}
+
+	close(release)
+	if err := <-patient; err != nil {
+		t.Fatalf("expected other caller to get the shared result, got %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected one upstream request, got %d", got)
+	}
+}
+
+func TestNwsAPI_GetForecast_OverallTimeout(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	c := NewClient(WithTransport(transport), WithTimeout(50*time.Millisecond), WithRequestTimeout(200*time.Millisecond))
+
+	start := time.Now()
+	_, err := c.GetForecast(context.Background(), testPoint, "")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected overall timeout to apply, took %v", elapsed)
+	}
+}
+
 // roundTripperFunc adapts a function to http.RoundTripper.
 type roundTripperFunc func(*http.Request) (*http.Response, error)
 
 func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestNwsAPI_GetForecast_ChoosesTodayInPointTimeZone(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1","timeZone":"America/Chicago"}}`
+		if req.URL.Path == "/forecast/1" {
+			body = `{"properties":{"periods":[
+				{"number":1,"name":"Overnight","startTime":"2024-03-02T02:00:00-06:00","endTime":"2024-03-02T06:00:00-06:00","isDaytime":false,"temperature":38,"shortForecast":"Clear"},
+				{"number":2,"name":"Saturday","startTime":"2024-03-02T06:00:00-06:00","endTime":"2024-03-02T18:00:00-06:00","isDaytime":true,"temperature":66,"shortForecast":"Mostly Sunny"}
+			]}}`
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	c := NewClient(WithTransport(transport)).(*nwsAPI)
+	// 08:30 UTC is still before dawn in Chicago
+	c.now = func() time.Time { return time.Date(2024, 3, 2, 8, 30, 0, 0, time.UTC) }
+
+	f, err := c.GetForecast(context.Background(), testPoint, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Period.Name != "Saturday" || f.Temperature != 66 || !f.Period.IsDaytime {
+		t.Fatalf("expected the Saturday daytime period, got %+v", f)
+	}
+}
+
+func TestNwsAPI_GetForecastPeriods(t *testing.T) {
+	t.Parallel()
+
+	var forecastCalls atomic.Int32
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+		if req.URL.Path == "/forecast/1" {
+			forecastCalls.Add(1)
+			body = `{"properties":{"periods":[
+				{"number":1,"name":"Today","isDaytime":true,"temperature":90,"temperatureUnit":"F","shortForecast":"Sunny"},
+				{"number":2,"name":"Tonight","isDaytime":false,"temperature":55,"temperatureUnit":"F","shortForecast":"Clear"}
+			]}}`
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	c := NewClient(WithTransport(transport))
+
+	periods, err := c.GetForecastPeriods(context.Background(), testPoint, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(periods.Periods) != 2 || periods.Periods[0].Name != "Today" || periods.Periods[1].Name != "Tonight" {
+		t.Fatalf("unexpected periods: %+v", periods)
+	}
+
+	// The forecast endpoint shares the cached NWS forecast
+	if _, err := c.GetForecast(context.Background(), testPoint, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := forecastCalls.Load(); got != 1 {
+		t.Fatalf("forecast fetched %d times, want 1", got)
+	}
+}
+
+func TestNwsAPI_Units(t *testing.T) {
+	t.Parallel()
+
+	var queries sync.Map
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+		if req.URL.Path == "/forecast/1" {
+			queries.Store(req.URL.RawQuery, true)
+			// A mirror that ignores units still gets converted locally
+			body = `{"properties":{"periods":[
+				{"number":1,"name":"Today","isDaytime":true,"temperature":86,"temperatureUnit":"F","shortForecast":"Sunny"}
+			]}}`
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	c := NewClient(WithTransport(transport), WithUnits(models.SI))
+
+	si, err := c.GetForecast(context.Background(), testPoint, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if si.Temperature != 30 || si.TemperatureUnit != "C" || si.Units != models.SI || si.Characterization != models.Hot {
+		t.Fatalf("expected the default SI units, got %+v", si)
+	}
+
+	us, err := c.GetForecast(context.Background(), testPoint, models.US)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if us.Temperature != 86 || us.TemperatureUnit != "F" || us.Units != models.US {
+		t.Fatalf("expected US units, got %+v", us)
+	}
+
+	for _, query := range []string{"units=si", ""} {
+		if _, ok := queries.Load(query); !ok {
+			t.Fatalf("forecast was not requested with %q", query)
+		}
+	}
+}
+
+func TestNwsAPI_GetHourlyForecast_StartsNow(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1","forecastHourly":"https://api.weather.gov/forecast/1/hourly"}}`
+		if req.URL.Path == "/forecast/1/hourly" {
+			body = `{"properties":{"periods":[
+				{"number":1,"startTime":"2024-03-01T14:00:00Z","endTime":"2024-03-01T15:00:00Z","temperature":61,"temperatureUnit":"F","shortForecast":"Sunny"},
+				{"number":2,"startTime":"2024-03-01T15:00:00Z","endTime":"2024-03-01T16:00:00Z","temperature":62,"temperatureUnit":"F","shortForecast":"Sunny"}
+			]}}`
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	c := NewClient(WithTransport(transport)).(*nwsAPI)
+	c.now = func() time.Time { return time.Date(2024, 3, 1, 15, 10, 0, 0, time.UTC) }
+
+	hourly, err := c.GetHourlyForecast(context.Background(), testPoint, "", models.HourlyQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hourly.Hours) != 1 || hourly.Hours[0].Temperature != 62 {
+		t.Fatalf("expected only the hour in effect now, got %+v", hourly.Hours)
+	}
+}
+
+func TestNwsAPI_MissingPointLinks(t *testing.T) {
+	t.Parallel()
+
+	// A point with a forecast but no hourly forecast or stations
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"properties":{"forecast":"https://api.weather.gov/forecast/1"}}`
+		if req.URL.Path == "/forecast/1" {
+			body = `{"properties":{"periods":[{"number":1,"name":"Today","isDaytime":true,"temperature":90,"temperatureUnit":"F","shortForecast":"Sunny"}]}}`
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	c := NewClient(WithTransport(transport))
+
+	calls := map[string]func() error{
+		"hourly": func() error {
+			_, err := c.GetHourlyForecast(context.Background(), testPoint, "", models.HourlyQuery{})
+			return err
+		},
+		"hourly in SI units": func() error {
+			_, err := c.GetHourlyForecast(context.Background(), testPoint, models.SI, models.HourlyQuery{})
+			return err
+		},
+		"conditions": func() error {
+			_, err := c.GetConditions(context.Background(), testPoint, "")
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		err := call()
+
+		var unavailable *DataUnavailableError
+		if !errors.As(err, &unavailable) || !errors.Is(err, ErrMissingLink) {
+			t.Fatalf("%s: expected missing link to be unavailable data, got %v", name, err)
+		}
+	}
+
+	// The daily forecast does without the hourly one
+	daily, err := c.GetDailyForecast(context.Background(), testPoint, "")
+	if err != nil || len(daily.Days) != 1 {
+		t.Fatalf("expected a daily forecast from the periods alone, got %+v, %v", daily, err)
+	}
+
+	// A point with no links at all has no forecast either
+	empty := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`{"properties":{}}`)), Header: make(http.Header)}, nil
+	})
+
+	if _, err := NewClient(WithTransport(empty)).GetForecast(context.Background(), testPoint, ""); !errors.Is(err, ErrMissingLink) {
+		t.Fatalf("forecast: expected missing link, got %v", err)
+	}
+	if _, err := NewClient(WithTransport(empty)).GetForecastPeriods(context.Background(), testPoint, ""); !errors.Is(err, ErrMissingLink) {
+		t.Fatalf("periods: expected missing link, got %v", err)
+	}
+	if _, err := NewClient(WithTransport(empty)).GetDailyForecast(context.Background(), testPoint, ""); !errors.Is(err, ErrMissingLink) {
+		t.Fatalf("daily: expected missing link, got %v", err)
+	}
+}
+
+func TestNwsAPI_GetAlerts(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		if req.URL.Path != "/alerts/active" || req.URL.Query().Get("point") != "1,2" {
+			return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(`{}`)), Header: make(http.Header)}, nil
+		}
+		body := `{"type":"FeatureCollection","features":[
+			{"properties":{"id":"a","event":"Tornado Warning","severity":"Extreme","expires":"2099-01-01T00:00:00Z"}},
+			{"properties":{"id":"b","event":"Wind Advisory","severity":"Minor","expires":"2099-01-01T00:00:00Z"}}
+		]}`
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	c := NewClient(WithTransport(transport))
+
+	all, err := c.GetAlerts(context.Background(), testPoint, models.AlertQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all.Alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got %+v", all.Alerts)
+	}
+
+	severe, err := c.GetAlerts(context.Background(), testPoint, models.AlertQuery{Severities: []string{"Extreme"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(severe.Alerts) != 1 || severe.Alerts[0].ID != "a" {
+		t.Fatalf("expected only the tornado warning, got %+v", severe.Alerts)
+	}
+
+	// Filters are applied to the one cached response for the point
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("alerts fetched %d times, want 1", got)
+	}
+}
+
+func TestNwsAPI_GetAlerts_NotServedStale(t *testing.T) {
+	t.Parallel()
+
+	var failing atomic.Bool
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if failing.Load() {
+			return nil, errors.New("network fail")
+		}
+		body := `{"features":[{"properties":{"id":"a","event":"Tornado Warning","expires":"2099-01-01T00:00:00Z"}}]}`
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+
+	for _, tc := range []struct {
+		name      string
+		opts      []Option
+		wantStale bool
+	}{
+		{"by default", nil, false},
+		{"within the alerts window", []Option{WithAlertsStaleIfError(10 * time.Minute)}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			failing.Store(false)
+
+			now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+			opts := append([]Option{WithTransport(transport), WithRetryPolicy(RetryPolicy{}), WithStaleWhileRevalidate(5 * time.Minute), WithStaleIfError(6 * time.Hour)}, tc.opts...)
+			c := NewClient(opts...).(*nwsAPI)
+			c.now = func() time.Time { return now }
+
+			if _, err := c.GetAlerts(context.Background(), testPoint, models.AlertQuery{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			failing.Store(true)
+			now = now.Add(3 * time.Minute)
+
+			alerts, err := c.GetAlerts(context.Background(), testPoint, models.AlertQuery{})
+			if !tc.wantStale {
+				var netErr *NetworkError
+				if !errors.As(err, &netErr) {
+					t.Fatalf("expected the upstream error despite the client's stale windows, got %+v, %v", alerts, err)
+				}
+				return
+			}
+			if err != nil || !alerts.Stale || len(alerts.Alerts) != 1 {
+				t.Fatalf("expected stale alerts, got %+v, %v", alerts, err)
+			}
+		})
+	}
+}
+
+func TestNwsAPI_GetConditions_FallsBack(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	stations := `{"features":[
+		{"id":"https://api.weather.gov/stations/A","geometry":{"coordinates":[2.0,1.0]},"properties":{"stationIdentifier":"A"}},
+		{"id":"https://api.weather.gov/stations/B","geometry":{"coordinates":[2.1,1.0]},"properties":{"stationIdentifier":"B"}},
+		{"id":"https://api.weather.gov/stations/C","geometry":{"coordinates":[2.2,1.0]},"properties":{"stationIdentifier":"C"}},
+		{"id":"https://api.weather.gov/stations/D","geometry":{"coordinates":[2.3,1.0]},"properties":{"stationIdentifier":"D"}}
+	]}`
+
+	tests := []struct {
+		name         string
+		observations map[string]string
+		wantStation  string
+		wantErr      func(error) bool
+	}{
+		{
+			name: "nearest station failing",
+			observations: map[string]string{
+				"B": `{"properties":{"timestamp":"2024-03-01T11:30:00Z","temperature":{"unitCode":"wmoUnit:degC","value":5}}}`,
+			},
+			wantStation: "B",
+		},
+		{
+			name: "old observation",
+			observations: map[string]string{
+				"A": `{"properties":{"timestamp":"2024-03-01T06:00:00Z","temperature":{"unitCode":"wmoUnit:degC","value":5}}}`,
+				"B": `{"properties":{"timestamp":"2024-03-01T11:30:00Z","temperature":{"unitCode":"wmoUnit:degC","value":null}}}`,
+				"C": `{"properties":{"timestamp":"2024-03-01T11:30:00Z","temperature":{"unitCode":"wmoUnit:degC","value":6}}}`,
+			},
+			wantStation: "C",
+		},
+		{
+			name: "no usable observation",
+			observations: map[string]string{
+				"A": `{"properties":{"timestamp":"2024-03-01T11:30:00Z","temperature":{"value":null}}}`,
+				"D": `{"properties":{"timestamp":"2024-03-01T11:30:00Z","temperature":{"unitCode":"wmoUnit:degC","value":6}}}`,
+			},
+			wantErr: func(err error) bool {
+				var unavailableErr *DataUnavailableError
+				return errors.As(err, &unavailableErr) && errors.Is(err, models.ErrNoObservation)
+			},
+		},
+		{
+			name:         "every station failing",
+			observations: map[string]string{},
+			wantErr: func(err error) bool {
+				var statusErr *UpstreamStatusError
+				return errors.As(err, &statusErr) && statusErr.StatusCode == 404
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				body := `{"properties":{"observationStations":"https://api.weather.gov/gridpoints/TOP/1,2/stations"}}`
+				switch {
+				case req.URL.Path == "/gridpoints/TOP/1,2/stations":
+					body = stations
+				case strings.HasSuffix(req.URL.Path, "/observations/latest"):
+					id := strings.Split(req.URL.Path, "/")[2]
+					observation, ok := tc.observations[id]
+					if !ok {
+						return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(`{"detail":"no observation"}`)), Header: make(http.Header)}, nil
+					}
+					body = observation
+				}
+				return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+			})
+
+			c := NewClient(WithTransport(transport)).(*nwsAPI)
+			c.now = func() time.Time { return now }
+
+			got, err := c.GetConditions(context.Background(), testPoint, "")
+
+			if tc.wantErr != nil {
+				if !tc.wantErr(err) {
+					t.Fatalf("unexpected error: %T: %v", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.StationID != tc.wantStation {
+				t.Fatalf("station: got %q want %q", got.StationID, tc.wantStation)
+			}
+		})
+	}
+}
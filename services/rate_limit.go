@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when a request to NWS could not get through
+// the outbound rate limit before its deadline.
+var ErrRateLimited = errors.New("outbound rate limit for NWS exceeded")
+
+// rateLimiter is a token bucket shared by every request a client sends to
+// NWS. It holds up to burst tokens and refills at rate tokens per second.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	burst = max(burst, 1)
+
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// wait blocks until a token is available. It fails with ErrRateLimited
+// straight away when the token would only arrive after ctx's deadline.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+
+	now := l.now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	// Taking the token up front, even into debt, queues callers fairly
+	l.tokens--
+	delay := time.Duration(max(-l.tokens, 0) / l.rate * float64(time.Second))
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Sub(now) < delay {
+		l.tokens++
+		l.mu.Unlock()
+		return ErrRateLimited
+	}
+
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_AllowsBurstThenThrottles(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := newRateLimiter(10, 3)
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	for i := 0; i < 3; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatalf("burst request %d: unexpected error: %v", i, err)
+		}
+	}
+
+	// the next token arrives after 100ms, which is past this deadline
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(50*time.Millisecond))
+	defer cancel()
+
+	if err := l.wait(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+
+	// the rejected request gave its token back
+	now = now.Add(100 * time.Millisecond)
+	ctx, cancel = context.WithDeadline(context.Background(), now.Add(time.Millisecond))
+	defer cancel()
+
+	if err := l.wait(ctx); err != nil {
+		t.Fatalf("expected refilled token, got %v", err)
+	}
+}
+
+func TestRateLimiter_Waits(t *testing.T) {
+	t.Parallel()
+
+	l := newRateLimiter(20, 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// one token up front, then two more at 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("expected requests to be spaced out, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_CancelledWait(t *testing.T) {
+	t.Parallel()
+
+	l := newRateLimiter(1, 1)
+	l.wait(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if l.tokens < -0.01 {
+		t.Fatalf("expected cancelled wait to give its token back, tokens=%v", l.tokens)
+	}
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	t.Parallel()
+
+	l := newRateLimiter(0, 0)
+
+	for i := 0; i < 100; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestFetch_IdentifiesClient(t *testing.T) {
+	t.Parallel()
+
+	var userAgent, accept, ifNoneMatch string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		accept = r.Header.Get("Accept")
+		ifNoneMatch = r.Header.Get("If-None-Match")
+		io.WriteString(w, `{}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient().(*nwsAPI)
+	if _, err := c.fetch(context.Background(), ts.URL, http.Header{"If-None-Match": {`"v1"`}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if userAgent != DefaultUserAgent {
+		t.Fatalf("User-Agent: got %q want %q", userAgent, DefaultUserAgent)
+	}
+	if accept != "application/geo+json" {
+		t.Fatalf("Accept: got %q", accept)
+	}
+	if ifNoneMatch != `"v1"` {
+		t.Fatalf("expected request headers to be kept, got If-None-Match %q", ifNoneMatch)
+	}
+
+	c = NewClient(WithUserAgent("(example.com, ops@example.com)")).(*nwsAPI)
+	if _, err := c.fetch(context.Background(), ts.URL, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userAgent != "(example.com, ops@example.com)" {
+		t.Fatalf("User-Agent: got %q", userAgent)
+	}
+}
+
+func TestFetch_SharesRateLimit(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithRateLimit(1, 1)).(*nwsAPI)
+
+	if _, err := c.fetch(context.Background(), ts.URL+"/a", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.fetch(ctx, ts.URL+"/b", nil); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected a different URL to share the limit, got %v", err)
+	}
+}
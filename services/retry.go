@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed GETs to NWS are retried. Network errors
+// and responses with one of RetryableStatuses are retried with capped
+// exponential backoff and jitter, or after the delay upstream asks for in
+// Retry-After. No retry is attempted once it could not finish before the
+// request's deadline.
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt; less than 2 disables retries.
+	MaxAttempts       int
+	BaseDelay         time.Duration
+	MaxDelay          time.Duration
+	RetryableStatuses []int
+}
+
+// DefaultRetryPolicy retries twice on network errors, rate limiting and
+// the 5xx responses NWS is known to return intermittently.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	RetryableStatuses: []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// backoff returns the delay before the attempt following attempt, which
+// is 1-based. It doubles from BaseDelay up to MaxDelay and is then jittered
+// into [delay/2, delay] so that clients failing together spread out.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+
+	return half + rand.N(delay-half+1)
+}
+
+// retryable reports whether a failed attempt is worth repeating.
+func (p RetryPolicy) retryable(resp *upstreamResponse, err error) bool {
+	if err != nil {
+		var linkErr *LinkNotAllowedError
+		return !errors.As(err, &linkErr)
+	}
+
+	return slices.Contains(p.RetryableStatuses, resp.statusCode)
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// fetch performs a GET against endpoint, retrying it according to the
+// client's RetryPolicy. Each attempt is bounded by requestTimeout and must
+// get past the rate limiter and the circuit breaker. Any final status other
+// than 200 or 304 is turned into an error.
+func (n *nwsAPI) fetch(ctx context.Context, endpoint string, header http.Header) (*upstreamResponse, error) {
+	for attempt := 1; ; attempt++ {
+		if err := n.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		if err := n.breaker.allow(); err != nil {
+			return nil, err
+		}
+
+		resp, err := n.attempt(ctx, endpoint, header)
+		n.breaker.record(upstreamFailed(resp, err))
+
+		// Only our own deadline or cancellation ends the request early
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if attempt >= n.retry.MaxAttempts || !n.retry.retryable(resp, err) {
+			if attempt > 1 {
+				log.Printf("GET %s finished after %d attempts", endpoint, attempt)
+			}
+
+			return checked(resp, err)
+		}
+
+		delay := n.retry.backoff(attempt)
+		reason := "network error"
+
+		if err == nil {
+			reason = strconv.Itoa(resp.statusCode)
+
+			if after, ok := retryAfter(resp.header, n.now()); ok {
+				delay = after
+			}
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			log.Printf("GET %s giving up after %d attempts: retry in %s would pass the deadline", endpoint, attempt, delay)
+
+			return checked(resp, err)
+		}
+
+		log.Printf("GET %s attempt %d failed (%s), retrying in %s", endpoint, attempt, reason, delay)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// attempt performs a single GET bounded by requestTimeout, identifying
+// the client to NWS as its terms of service require.
+func (n *nwsAPI) attempt(ctx context.Context, endpoint string, header http.Header) (*upstreamResponse, error) {
+	if n.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.requestTimeout)
+		defer cancel()
+	}
+
+	header = header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set("User-Agent", n.userAgent)
+	header.Set("Accept", "application/geo+json")
+
+	return roundTrip(ctx, n.httpClient, endpoint, header)
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{10, time.Second},
+	}
+
+	for _, tc := range tests {
+		for i := 0; i < 50; i++ {
+			got := p.backoff(tc.attempt)
+			if got < tc.max/2 || got > tc.max {
+				t.Fatalf("attempt %d: backoff %v outside [%v, %v]", tc.attempt, got, tc.max/2, tc.max)
+			}
+		}
+	}
+
+	if got := (RetryPolicy{}).backoff(3); got != 0 {
+		t.Fatalf("expected zero backoff without a base delay, got %v", got)
+	}
+}
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	t.Parallel()
+
+	p := DefaultRetryPolicy
+
+	if !p.retryable(nil, errors.New("network fail")) {
+		t.Fatal("expected network errors to be retryable")
+	}
+
+	for _, status := range []int{429, 500, 502, 503, 504} {
+		if !p.retryable(&upstreamResponse{statusCode: status}, nil) {
+			t.Fatalf("expected %d to be retryable", status)
+		}
+	}
+
+	for _, status := range []int{400, 404, 501} {
+		if p.retryable(&upstreamResponse{statusCode: status}, nil) {
+			t.Fatalf("expected %d not to be retryable", status)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"missing", "", 0, false},
+		{"seconds", "3", 3 * time.Second, true},
+		{"http date", now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"garbage", "soon", 0, false},
+		{"negative", "-1", 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			header := make(http.Header)
+			if tc.value != "" {
+				header.Set("Retry-After", tc.value)
+			}
+
+			got, ok := retryAfter(header, now)
+			if got != tc.want || ok != tc.wantOK {
+				t.Fatalf("got %v,%v want %v,%v", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func fastRetries(attempts int) Option {
+	return WithRetryPolicy(RetryPolicy{
+		MaxAttempts:       attempts,
+		BaseDelay:         time.Millisecond,
+		MaxDelay:          5 * time.Millisecond,
+		RetryableStatuses: DefaultRetryPolicy.RetryableStatuses,
+	})
+}
+
+func TestFetch_RetriesTransientFailures(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		io.WriteString(w, `{}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(fastRetries(3)).(*nwsAPI)
+
+	resp, err := c.fetch(context.Background(), ts.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.statusCode != http.StatusOK || requests.Load() != 3 {
+		t.Fatalf("expected success on the third attempt, got status %d after %d requests", resp.statusCode, requests.Load())
+	}
+}
+
+func TestFetch_GivesUpAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, `{"detail":"try later"}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(fastRetries(4)).(*nwsAPI)
+
+	_, err := c.fetch(context.Background(), ts.URL, nil)
+	if err == nil || err.Error() != "try later" {
+		t.Fatalf("expected final upstream error, got %v", err)
+	}
+	if got := requests.Load(); got != 4 {
+		t.Fatalf("expected 4 attempts, got %d", got)
+	}
+}
+
+func TestFetch_DoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"detail":"no such point"}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(fastRetries(3)).(*nwsAPI)
+
+	if _, err := c.fetch(context.Background(), ts.URL, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected a single attempt, got %d", got)
+	}
+}
+
+func TestFetch_RetriesNetworkErrors(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if requests.Add(1) == 1 {
+			return nil, errors.New("connection reset")
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(http.NoBody), Header: make(http.Header)}, nil
+	})
+
+	c := NewClient(WithTransport(transport), fastRetries(2)).(*nwsAPI)
+
+	if _, err := c.fetch(context.Background(), "https://api.weather.gov/points/1,2", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestFetch_HonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	var first, second atomic.Int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			first.Store(time.Now().UnixNano())
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		second.Store(time.Now().UnixNano())
+		io.WriteString(w, `{}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(fastRetries(2)).(*nwsAPI)
+
+	if _, err := c.fetch(context.Background(), ts.URL, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if waited := time.Duration(second.Load() - first.Load()); waited < time.Second {
+		t.Fatalf("expected to wait for Retry-After, waited %v", waited)
+	}
+}
+
+func TestFetch_StopsWhenRetryWouldPassDeadline(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, `{"detail":"maintenance"}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(fastRetries(3)).(*nwsAPI)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.fetch(ctx, ts.URL, nil)
+
+	if err == nil || err.Error() != "maintenance" {
+		t.Fatalf("expected upstream error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected to give up without waiting, took %v", elapsed)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected a single attempt, got %d", got)
+	}
+}
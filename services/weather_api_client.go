@@ -1,11 +1,229 @@
 package services
 
-import "github.com/rmccullagh/weather-api/models"
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
 
+	"github.com/rmccullagh/weather-api/models"
+	"golang.org/x/sync/singleflight"
+)
+
+// WeatherClient looks up weather from NWS. Forecasts and conditions are
+// given in units, or the client's default unit system when it is empty.
 type WeatherClient interface {
-	GetForecast(latitude, longitude string) (*models.Forecast, error)
+	// GetForecast returns the forecast for today at coordinate.
+	GetForecast(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.Forecast, error)
+	// GetForecastPeriods returns every period of the multi-day forecast.
+	GetForecastPeriods(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.ForecastPeriods, error)
+	// GetHourlyForecast returns the hours of the hourly forecast selected
+	// by query.
+	GetHourlyForecast(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem, query models.HourlyQuery) (*models.HourlyForecast, error)
+	// GetDailyForecast returns the forecast summarised per local day.
+	GetDailyForecast(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.DailyForecast, error)
+	// GetAlerts returns the active alerts for coordinate that match query.
+	GetAlerts(ctx context.Context, coordinate models.Coordinate, query models.AlertQuery) (*models.Alerts, error)
+	// GetConditions returns the conditions last observed near coordinate.
+	GetConditions(ctx context.Context, coordinate models.Coordinate, units models.UnitSystem) (*models.Conditions, error)
+}
+
+// Option configures the client returned by NewClient.
+type Option func(*nwsAPI)
+
+// WithHTTPClient sets the HTTP client used to reach NWS instead of
+// http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(n *nwsAPI) {
+		n.httpClient = client
+	}
+}
+
+// WithTransport sets the transport used to reach NWS, on top of whichever
+// HTTP client is configured.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(n *nwsAPI) {
+		n.transport = transport
+	}
+}
+
+// WithBaseURL points the client at another NWS API deployment, such as a
+// staging mirror or a local stand-in server, instead of DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(n *nwsAPI) {
+		n.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithAllowedOrigins allows links in NWS responses to point at the given
+// origins, such as "https://api.weather.gov", in addition to the base URL's.
+// This is needed for mirrors that link back to NWS itself.
+func WithAllowedOrigins(origins ...string) Option {
+	return func(n *nwsAPI) {
+		for _, o := range origins {
+			if allowed, ok := parseOrigin(o); ok {
+				n.allowedOrigins = append(n.allowedOrigins, allowed)
+			}
+		}
+	}
+}
+
+// WithCacheTTLs sets how long points metadata and forecasts are considered
+// fresh when upstream does not send Cache-Control or Expires headers.
+// With a non-positive duration such responses are revalidated every time.
+func WithCacheTTLs(points, forecast time.Duration) Option {
+	return func(n *nwsAPI) {
+		n.pointsTTL = points
+		n.forecastTTL = forecast
+	}
+}
+
+// WithAlertsTTL sets how long active alerts are considered fresh when
+// upstream does not send Cache-Control or Expires headers.
+func WithAlertsTTL(ttl time.Duration) Option {
+	return func(n *nwsAPI) {
+		n.alertsTTL = ttl
+	}
+}
+
+// WithAlertsStaleIfError lets expired alerts be served for up to maxStale
+// past their expiry when fetching fresh ones fails. It replaces
+// WithStaleIfError for alerts and defaults to zero, failing instead, since
+// stale alerts miss any warning issued after them.
+func WithAlertsStaleIfError(maxStale time.Duration) Option {
+	return func(n *nwsAPI) {
+		n.alertsStaleIfError = maxStale
+	}
+}
+
+// WithCacheSize bounds the number of cached responses. Least recently used
+// entries are evicted first; a non-positive size disables caching.
+func WithCacheSize(maxEntries int) Option {
+	return func(n *nwsAPI) {
+		n.cache = NewMemoryCache(maxEntries)
+	}
+}
+
+// WithCache replaces the default in-memory cache, for example with a
+// FileCache so cached responses survive restarts.
+func WithCache(cache Cache) Option {
+	return func(n *nwsAPI) {
+		n.cache = cache
+	}
+}
+
+// WithStaleWhileRevalidate lets an expired forecast be served for up to
+// window past its expiry while it is refreshed in the background.
+func WithStaleWhileRevalidate(window time.Duration) Option {
+	return func(n *nwsAPI) {
+		n.staleWhileRevalidate = window
+	}
 }
 
-func NewClient() WeatherClient {
-	return &nwsAPI{}
+// WithStaleIfError lets an expired forecast be served for up to maxStale
+// past its expiry when fetching a fresh one from upstream fails.
+func WithStaleIfError(maxStale time.Duration) Option {
+	return func(n *nwsAPI) {
+		n.staleIfError = maxStale
+	}
+}
+
+// WithRequestTimeout bounds each request made to NWS, including each retry
+// of it. A non-positive timeout leaves requests bounded only by the
+// overall timeout.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(n *nwsAPI) {
+		n.requestTimeout = timeout
+	}
+}
+
+// WithTimeout bounds each call to the client, including every request to
+// NWS it makes. A non-positive timeout leaves calls bounded only by the
+// caller's context.
+func WithTimeout(timeout time.Duration) Option {
+	return func(n *nwsAPI) {
+		n.timeout = timeout
+	}
+}
+
+// WithRetryPolicy sets how failed requests to NWS are retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(n *nwsAPI) {
+		n.retry = policy
+	}
+}
+
+// WithBreakerPolicy sets when the circuit breaker around NWS opens and
+// how it probes for recovery.
+func WithBreakerPolicy(policy BreakerPolicy) Option {
+	return func(n *nwsAPI) {
+		n.breakerPolicy = policy
+	}
+}
+
+// WithUserAgent sets the User-Agent sent to NWS. NWS asks for one that
+// identifies the application and a way to contact its operator, e.g.
+// "(myweatherapp.com, contact@myweatherapp.com)".
+func WithUserAgent(userAgent string) Option {
+	return func(n *nwsAPI) {
+		n.userAgent = userAgent
+	}
+}
+
+// WithRateLimit limits requests to NWS to perSecond on average, allowing
+// bursts of up to burst requests. The limit is shared by every call made
+// through the client. A non-positive rate disables the limit.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(n *nwsAPI) {
+		n.limiter = newRateLimiter(perSecond, burst)
+	}
+}
+
+// WithUnits sets the unit system used when a call does not ask for one,
+// instead of models.US.
+func WithUnits(units models.UnitSystem) Option {
+	return func(n *nwsAPI) {
+		n.units = units
+	}
+}
+
+func NewClient(opts ...Option) WeatherClient {
+	n := &nwsAPI{
+		httpClient:     http.DefaultClient,
+		baseURL:        DefaultBaseURL,
+		cache:          NewMemoryCache(DefaultCacheMaxEntries),
+		now:            time.Now,
+		flights:        &singleflight.Group{},
+		pointsTTL:      defaultPointsTTL,
+		forecastTTL:    defaultForecastTTL,
+		alertsTTL:      defaultAlertsTTL,
+		observationTTL: defaultObservationTTL,
+		requestTimeout: defaultRequestTimeout,
+		timeout:        defaultTimeout,
+		retry:          DefaultRetryPolicy,
+		breakerPolicy:  DefaultBreakerPolicy,
+		limiter:        newRateLimiter(defaultRateLimit, defaultRateBurst),
+		userAgent:      DefaultUserAgent,
+		units:          models.US,
+	}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	if n.transport != nil {
+		client := *n.httpClient
+		client.Transport = n.transport
+		n.httpClient = &client
+	}
+
+	if base, ok := parseOrigin(n.baseURL); ok {
+		n.allowedOrigins = append(n.allowedOrigins, base)
+	}
+
+	n.httpClient = n.checkRedirects(n.httpClient)
+
+	n.breaker = newBreaker(n.breakerPolicy, func() time.Time { return n.now() })
+
+	return n
 }
@@ -1,8 +1,17 @@
 package services
 
-import "testing"
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
 
 func TestNewClient_NotNil(t *testing.T) {
+	t.Parallel()
+
 	c := NewClient()
 	if c == nil {
 		t.Fatal("NewClient returned nil")
@@ -10,8 +19,99 @@ func TestNewClient_NotNil(t *testing.T) {
 }
 
 func TestNewClient_ConcreteType(t *testing.T) {
+	t.Parallel()
+
 	c := NewClient()
 	if _, ok := c.(*nwsAPI); !ok {
 		t.Fatalf("expected *nwsAPI concrete type, got %T", c)
 	}
 }
+
+func TestNewClient_Options(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient(WithCacheTTLs(time.Hour, time.Minute), WithCacheSize(5)).(*nwsAPI)
+
+	if c.pointsTTL != time.Hour || c.forecastTTL != time.Minute {
+		t.Fatalf("unexpected ttls: points=%v forecast=%v", c.pointsTTL, c.forecastTTL)
+	}
+	if cache, ok := c.cache.(*MemoryCache); !ok || cache.maxEntries != 5 {
+		t.Fatalf("unexpected cache: %#v", c.cache)
+	}
+}
+
+func TestNewClient_Timeouts(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient().(*nwsAPI)
+	if c.requestTimeout != defaultRequestTimeout || c.timeout != defaultTimeout {
+		t.Fatalf("unexpected default timeouts: request=%v overall=%v", c.requestTimeout, c.timeout)
+	}
+
+	c = NewClient(WithRequestTimeout(time.Second), WithTimeout(3*time.Second)).(*nwsAPI)
+	if c.requestTimeout != time.Second || c.timeout != 3*time.Second {
+		t.Fatalf("unexpected timeouts: request=%v overall=%v", c.requestTimeout, c.timeout)
+	}
+}
+
+func TestNewClient_WithCache(t *testing.T) {
+	t.Parallel()
+
+	cache := NewMemoryCache(1)
+	c := NewClient(WithCache(cache)).(*nwsAPI)
+
+	if c.cache != cache {
+		t.Fatalf("expected custom cache to be used, got %#v", c.cache)
+	}
+}
+
+func TestNewClient_HTTPOptions(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient().(*nwsAPI)
+	// A copy of http.DefaultClient that checks where redirects lead
+	if c.httpClient == http.DefaultClient || c.httpClient.Transport != nil || c.httpClient.CheckRedirect == nil || c.baseURL != DefaultBaseURL {
+		t.Fatalf("unexpected defaults: client=%#v baseURL=%q", c.httpClient, c.baseURL)
+	}
+
+	custom := &http.Client{Timeout: time.Second}
+	transport := roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+	c = NewClient(WithTransport(transport), WithHTTPClient(custom), WithBaseURL("http://mirror.local/")).(*nwsAPI)
+
+	if c.baseURL != "http://mirror.local" {
+		t.Fatalf("expected trailing slash to be trimmed, got %q", c.baseURL)
+	}
+	if c.httpClient == custom || c.httpClient.Timeout != time.Second || c.httpClient.Transport == nil {
+		t.Fatalf("expected a copy of the custom client using the transport, got %#v", c.httpClient)
+	}
+	if custom.Transport != nil {
+		t.Fatal("expected the caller's client not to be modified")
+	}
+}
+
+func TestNwsAPI_GetForecast_WithBaseURL(t *testing.T) {
+	t.Parallel()
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/points/1,2":
+			io.WriteString(w, `{"properties":{"forecast":"`+ts.URL+`/gridpoints/TOP/1,2/forecast"}}`)
+		case "/gridpoints/TOP/1,2/forecast":
+			io.WriteString(w, `{"properties":{"periods":[{"shortForecast":"Mirrored","temperature":70}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+
+	f, err := c.GetForecast(context.Background(), testPoint, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.ForecastDaily != "Mirrored" || f.Temperature != 70 {
+		t.Fatalf("unexpected forecast: %#v", f)
+	}
+}
@@ -3,6 +3,8 @@ package utils
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/rmccullagh/weather-api/models"
 )
 
 func JSONResponse(w http.ResponseWriter, obj interface{}) {
@@ -10,3 +12,11 @@ func JSONResponse(w http.ResponseWriter, obj interface{}) {
 	encoder.SetIndent("", "    ")
 	encoder.Encode(obj)
 }
+
+// ProblemResponse writes problem as application/problem+json with its
+// status.
+func ProblemResponse(w http.ResponseWriter, problem models.Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	JSONResponse(w, problem)
+}
@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/rmccullagh/weather-api/models"
 )
 
 func TestJSONResponse_Struct(t *testing.T) {
@@ -37,3 +40,22 @@ func TestJSONResponse_Nil(t *testing.T) {
 		t.Fatalf("unexpected body for nil: got %q want %q", body, expected)
 	}
 }
+
+func TestProblemResponse(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	ProblemResponse(rr, models.NewProblem(http.StatusBadGateway, "upstream failed"))
+
+	if rr.Code != http.StatusBadGateway {
+		t.Fatalf("unexpected status code: got %d want %d", rr.Code, http.StatusBadGateway)
+	}
+
+	if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("unexpected content type: %q", ct)
+	}
+
+	expected := "{\n    \"type\": \"about:blank\",\n    \"title\": \"Bad Gateway\",\n    \"status\": 502,\n    \"detail\": \"upstream failed\"\n}\n"
+	if body := rr.Body.String(); body != expected {
+		t.Fatalf("unexpected body:\n got: %q\nwant: %q", body, expected)
+	}
+}